@@ -0,0 +1,131 @@
+package main
+
+// Assistant mode: help the user play a game somewhere else. Usage:
+// ./cli -c wordle.corpus -a webster-2-all-five-letter.corpus -assist -s gmobot,minimax -- gmobot:wordle.corpus
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+// The most candidates listed after each guess
+const maxListed = 20
+
+// assist reads the guesses the user made in another game, each followed
+// by the colours it was given, e.g. "crane g.y..". After each one it
+// lists the answers that are still possible, found with gmobot's filter,
+// and the guess each of the strategies would make next. There is no
+// engine and no goal word. If no noninteractive strategy was selected,
+// gmobot and minimax make the recommendations.
+func assist(words *WordLists, selectedStrategies []Strategy) {
+	var strategies []Strategy
+	for _, s := range selectedStrategies {
+		if !s.interactive {
+			strategies = append(strategies, s)
+		}
+	}
+	if len(strategies) == 0 {
+		for _, s := range registeredStrategies {
+			if s.name == "gmobot" || s.name == "minimax" {
+				strategies = append(strategies, s)
+			}
+		}
+	}
+	bots := make([]Bot, len(strategies))
+	for k, s := range strategies {
+		bots[k] = s.newBot()
+	}
+	defer closeBots(bots)
+	if console == nil {
+		console = bufio.NewReader(os.Stdin)
+	}
+
+	fmt.Printf("Enter each guess and its colours, e.g. \"crane g.y..\" (g green, y yellow, . gray),\n")
+	fmt.Printf("\"new\" to start a new game, or a blank line to quit.\n")
+	var turns []Turn
+	recommend(words, strategies, bots, turns)
+	for {
+		fmt.Printf("assist> ")
+		text, err := console.ReadString('\n')
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return // a blank line or the end of the input
+		}
+		if text == "new" {
+			turns = nil
+			recommend(words, strategies, bots, turns)
+			continue
+		}
+		turn, err := parseFeedback(text, len(words.Answers[0]))
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			continue
+		}
+		turns = append(turns, turn)
+		if turn.NCorrect == len(turn.Guess) {
+			fmt.Printf("Solved in %d guesses\n", len(turns))
+			turns = nil
+		}
+		recommend(words, strategies, bots, turns)
+	}
+}
+
+// parseFeedback parses a guess followed by its colours, one of g, y or .
+// for each letter, and returns them as a Turn.
+func parseFeedback(text string, wordLength int) (Turn, error) {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) != 2 {
+		return Turn{}, fmt.Errorf("expected a guess and its colours, e.g. \"crane g.y..\"")
+	}
+	guess, colours := fields[0], fields[1]
+	if len(guess) != wordLength || len(colours) != wordLength {
+		return Turn{}, fmt.Errorf("the guess and its colours must have %d letters", wordLength)
+	}
+	signature := make([]byte, wordLength)
+	for i := 0; i < wordLength; i++ {
+		switch colours[i] {
+		case 'g':
+			signature[i] = gtw.LETTER_CORRECT
+		case 'y':
+			signature[i] = gtw.LETTER_IN_WORD
+		case '.', '-', 'b', 'x':
+			signature[i] = gtw.LETTER_WRONG
+		default:
+			return Turn{}, fmt.Errorf("bad colour %c: use g for green, y for yellow and . for gray", colours[i])
+		}
+	}
+	return Turn{
+		Guess:     guess,
+		Signature: string(signature),
+		NCorrect:  strings.Count(string(signature), string(gtw.LETTER_CORRECT)),
+	}, nil
+}
+
+// recommend lists the candidates left after the turns and asks each bot
+// for its next guess. Each bot starts a new game when there are no turns.
+func recommend(words *WordLists, strategies []Strategy, bots []Bot, turns []Turn) {
+	if len(turns) > 0 {
+		candidates := words.Answers
+		for _, t := range turns {
+			candidates = filter(candidates, t.Guess, t.Signature)
+		}
+		switch {
+		case len(candidates) == 0:
+			fmt.Printf("No answers fit; check the colours\n")
+		case len(candidates) <= maxListed:
+			fmt.Printf("%d candidates: %s\n", len(candidates), strings.Join(candidates, " "))
+		default:
+			fmt.Printf("%d candidates, including: %s\n", len(candidates), strings.Join(candidates[:maxListed], " "))
+		}
+	}
+	for k, s := range strategies {
+		if len(turns) == 0 {
+			bots[k].NewGame(words)
+		}
+		fmt.Printf("  %s recommends %s\n", s.name, bots[k].Guess(turns))
+	}
+}
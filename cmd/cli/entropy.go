@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// NewEntropyBot returns the "entropy" strategy. Each guess is chosen to
+// maximize the expected information gained from its signature, i.e. the
+// Shannon entropy of the distribution of signatures over the answers that
+// are still possible. See SolverBot.
+func NewEntropyBot() Bot {
+	return &SolverBot{goodness: entropy, fullList: true}
+}
+
+// entropy returns the Shannon entropy in bits of the distribution of
+// signatures given the number of candidates producing each signature.
+// The terms are summed in a fixed order so that rounding, and therefore
+// the choice between guesses of nearly equal entropy, is reproducible.
+func entropy(buckets map[string]int, total int) float64 {
+	counts := make([]int, 0, len(buckets))
+	for _, n := range buckets {
+		counts = append(counts, n)
+	}
+	sort.Ints(counts)
+	h := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,266 @@
+package main
+
+// Bots running as external processes. Usage:
+// ./cli -c wordle.corpus -s "exec:python3 mybot.py" -j 4
+//
+// The harness starts the command once for each worker and talks to it
+// over its standard input and output, one message per line:
+//
+//	answers N      followed by N lines, the possible goal words
+//	guesses N      followed by N lines, the words accepted as guesses
+//	new            a new game is starting
+//	result G S     guess G was scored with signature S
+//	invalid G      guess G was rejected by the engine, so it wasn't a turn
+//	guess          the bot must reply with one line holding its next guess
+//	end W          the game is over and the goal word was W
+//
+// The word lists are sent once, when the process starts. The signatures
+// use the engine's characters: '+' for a correct letter, '*' for a letter
+// in the word but in the wrong place, and '#' for a letter not in the
+// word (see gtw.Score). Before each "guess" the bot is sent the results
+// of its earlier guesses not yet reported. A bot replying with an empty
+// line gives up the game. A minimal bot in Python, which guesses the
+// answers in order:
+//
+//	import sys
+//	words, i = [], 0
+//	for line in sys.stdin:
+//	    msg = line.split()
+//	    if msg[0] == "answers":
+//	        words = [sys.stdin.readline().strip() for _ in range(int(msg[1]))]
+//	    elif msg[0] == "guesses":
+//	        for _ in range(int(msg[1])): sys.stdin.readline()
+//	    elif msg[0] == "new":
+//	        i = 0
+//	    elif msg[0] == "guess":
+//	        print(words[i], flush=True)
+//	        i += 1
+//
+// When the harness has no more games for the bot it closes the bot's
+// standard input, and the bot should then exit; a non-zero exit status is
+// reported. A bot that doesn't read its input, reply to "guess" or exit
+// within the time set by -exectimeout is killed, and gives up its games
+// from then on. Standard error is passed through, so the bot can use it
+// for messages. Because -s is a comma-separated list, the command cannot
+// contain a comma.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const execPrefix = "exec:"
+
+// ExecBot is a bot running as an external process.
+type ExecBot struct {
+	command   string
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	pending   bytes.Buffer // messages not yet written to the process
+	replies   chan string  // lines from the process, closed at the end
+	started   bool
+	failed    bool
+	killed    bool
+	sent      []Turn // the turns of this game sent to the bot
+	lastGuess string // the bot's last guess, "" at the start of a game
+}
+
+func NewExecBot(command string) Bot {
+	return &ExecBot{command: command}
+}
+
+// start starts the process and sends it the word lists.
+func (b *ExecBot) start(words *WordLists) {
+	b.started = true
+	args := strings.Fields(b.command)
+	if len(args) == 0 {
+		b.fail(fmt.Errorf("no command"))
+		return
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		b.fail(err)
+		return
+	}
+	b.cmd = cmd
+	b.stdin = in
+	// The lines are read in the background so that waiting for a reply
+	// can time out.
+	b.replies = make(chan string)
+	go func() {
+		defer close(b.replies)
+		r := bufio.NewReader(out)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			b.replies <- line
+		}
+	}()
+	for _, list := range []struct {
+		name  string
+		words []string
+	}{{"answers", words.Answers}, {"guesses", words.Guesses}} {
+		b.send("%s %d", list.name, len(list.words))
+		for _, w := range list.words {
+			b.send("%s", w)
+		}
+	}
+}
+
+func (b *ExecBot) NewGame(words *WordLists) {
+	if !b.started {
+		b.start(words)
+	}
+	b.sent = nil
+	b.lastGuess = ""
+	b.send("new")
+}
+
+func (b *ExecBot) Guess(turns []Turn) string {
+	b.report(turns)
+	b.send("guess")
+	if b.failed || b.flush() != nil {
+		return ""
+	}
+	select {
+	case line, ok := <-b.replies:
+		if !ok {
+			b.fail(fmt.Errorf("the bot closed its output"))
+			return ""
+		}
+		b.lastGuess = strings.TrimSpace(line)
+		return b.lastGuess
+	case <-time.After(*execTimeout):
+		b.kill(fmt.Errorf("no guess after %s", *execTimeout))
+		return ""
+	}
+}
+
+func (b *ExecBot) EndGame(goal string, turns []Turn) {
+	b.report(turns)
+	b.send("end %s", goal)
+	b.flush()
+}
+
+// Close closes the process's standard input, which tells it there are no
+// more games, and waits for it to exit, killing it if it doesn't. A
+// non-zero exit status is reported.
+func (b *ExecBot) Close() error {
+	if b.cmd == nil {
+		return nil
+	}
+	b.flush()
+	b.stdin.Close()
+	// Anything more the bot writes is ignored
+	timeout := time.After(*execTimeout)
+	for done := false; !done; {
+		select {
+		case _, ok := <-b.replies:
+			done = !ok
+		case <-timeout:
+			b.kill(fmt.Errorf("still running %s after its last game", *execTimeout))
+			done = true
+		}
+	}
+	err := b.cmd.Wait()
+	if err != nil && !b.killed {
+		fmt.Fprintf(os.Stderr, "%s%s: %s\n", execPrefix, b.command, err)
+	}
+	b.cmd = nil
+	return err
+}
+
+// report sends the turns not yet sent to the bot. If no turn was added
+// since the bot's last guess, the guess was rejected. In a multi-board
+// game the turns move to another board when one is solved; the bot is
+// then sent a new game and all of that board's turns.
+func (b *ExecBot) report(turns []Turn) {
+	if len(turns) < len(b.sent) || !sameTurns(b.sent, turns[:len(b.sent)]) {
+		b.send("new")
+		b.sent = nil
+	} else if len(turns) == len(b.sent) && b.lastGuess != "" {
+		b.send("invalid %s", b.lastGuess)
+	}
+	for _, t := range turns[len(b.sent):] {
+		b.send("result %s %s", t.Guess, t.Signature)
+	}
+	b.sent = append([]Turn(nil), turns...)
+}
+
+func sameTurns(a []Turn, b []Turn) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// send adds a message to those to be written by the next flush.
+func (b *ExecBot) send(format string, args ...interface{}) {
+	if b.failed {
+		return
+	}
+	fmt.Fprintf(&b.pending, format+"\n", args...)
+}
+
+// flush writes the messages sent since the last flush. A bot that hasn't
+// read them within the timeout is killed.
+func (b *ExecBot) flush() error {
+	if b.failed {
+		return fmt.Errorf("failed")
+	}
+	data := append([]byte(nil), b.pending.Bytes()...)
+	b.pending.Reset()
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.stdin.Write(data)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			b.fail(err)
+		}
+		return err
+	case <-time.After(*execTimeout):
+		err := fmt.Errorf("not reading its input after %s", *execTimeout)
+		b.kill(err)
+		return err
+	}
+}
+
+// kill reports the error and kills the process.
+func (b *ExecBot) kill(err error) {
+	b.fail(err)
+	b.killed = true
+	b.cmd.Process.Kill()
+}
+
+// fail reports the first error talking to the process. From then on the
+// bot gives up every game.
+func (b *ExecBot) fail(err error) {
+	if !b.failed {
+		fmt.Fprintf(os.Stderr, "%s%s: %s\n", execPrefix, b.command, err)
+	}
+	b.failed = true
+}
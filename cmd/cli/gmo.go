@@ -16,54 +16,88 @@ import (
 
 const prefix = "gmobot:"
 
-// initialization state machine
-var initialized bool
-var initFailed bool
+// GmoBot holds the state of one instance of the bot. Each instance
+// loads its own word list, so instances can play in parallel.
+type GmoBot struct {
+	// initialization state machine
+	initialized bool
+	initFailed bool
 
-// one-time initialization - all guesses come from these words
-var masterWordList []string
+	// one-time initialization - all guesses come from these words
+	masterWordList []string
 
-// per-game
-var guesses []string
+	// per-game
+	wordLength int
+	lastGuess  string
+	lastTurns  int
+	rejected   []string // guesses the engine wouldn't score
+}
+
+func NewGmoBot() Bot {
+	return &GmoBot{}
+}
 
-func GmoGuess(corpus []string, scores []string, nCorrect int) string {
-	if ! initialized {
-		botInit(corpus)
-		if len(masterWordList) == 0 {
+func (b *GmoBot) NewGame(words *WordLists) {
+	if ! b.initialized {
+		b.botInit(words)
+		if len(b.masterWordList) == 0 {
 			fmt.Fprintf(os.Stderr, "gmobot: bot initialized failed\n")
-			initFailed = true
+			b.initFailed = true
 		}
-		initialized = true
+		b.initialized = true
 	}
+	b.wordLength = len(words.Answers[0])
+	b.lastGuess = ""
+	b.rejected = nil
+}
 
-	if initFailed {
-		return "?????"
+func (b *GmoBot) Guess(turns []Turn) string {
+	if b.initFailed {
+		return strings.Repeat("?", b.wordLength)
 	}
 
-	if len(scores) == 0 { //new game
-		guesses = make([]string, 0, 0)
+	// fmt.Printf("gmobot: turns: %v\n", turns)
+	remaining := b.masterWordList
+	for _, t := range turns {
+		remaining = filter(remaining, t.Guess, t.Signature)
 	}
-	
-	// fmt.Printf("gmobot: scores: %v\n", scores)
-	remaining := masterWordList
-	for i := range(guesses) {
-		remaining = filter(remaining, guesses[i], scores[i])
+
+	// If no turn was added since the last guess, the engine rejected it
+	// (e.g. in hard mode). Don't make it again.
+	if b.lastGuess != "" && len(turns) == b.lastTurns {
+		b.rejected = append(b.rejected, b.lastGuess)
+	}
+	if len(b.rejected) > 0 {
+		var allowed []string
+		for _, w := range remaining {
+			if findStringInSlice(w, b.rejected) < 0 {
+				allowed = append(allowed, w)
+			}
+		}
+		if len(allowed) == 0 && len(remaining) > 0 {
+			return "" // every possible word was rejected, give up
+		}
+		remaining = allowed
 	}
 
 	frequencies := computeLetterFrequencies(remaining)
 	guess := choose(remaining, frequencies)
-	guesses = append(guesses, guess)
+	b.lastGuess = guess
+	b.lastTurns = len(turns)
 	// fmt.Printf("gmobot: guess: %s\n", guess)
 	return guess
 }
 
+func (b *GmoBot) EndGame(goal string, turns []Turn) {
+}
+
 // filter returns a subset of the argument word list. The subset is constructed
 // by removing all the words that are no longer possible given the score and
-// the guess. The guess is a 5-letter word and the score is a signature returned
-// from GtwEngine.Score().
+// the guess. The guess is a word of any length and the score is a signature
+// returned from GtwEngine.Score().
 
 func filter(words []string, guess string, score string) []string {
-	var regexComponents = []string{"", "", "", "", ""}
+	var regexComponents = make([]string, len(guess))
 
 	// Create a stoplist having all the out-of-place letters. This handles a
 	// corner case where a letter is both out-of-place and wrong (this can
@@ -79,7 +113,8 @@ func filter(words []string, guess string, score string) []string {
 
 	// Construct negative patterns for letters neither in word nor in stoplist.
 	// If the letters r, t, and e are not in the solution, we want to construct:
-	// [^rte][^rte][^rte][^rte][^rte] as the regex (and then add other features)
+	// [^rte][^rte][^rte][^rte][^rte] as the regex for a 5-letter word (and then
+	// add other features)
 	// fmt.Printf("filter: scores %v | guesses %v\n", scores, guesses)
 	for i, r := range(score) {
 		if r == gtw.LETTER_WRONG {
@@ -123,11 +158,11 @@ func filter(words []string, guess string, score string) []string {
 	}
 
 	// Compile the regex
-	re := regexComponents[0] + regexComponents[1] + regexComponents[2] + regexComponents[3] + regexComponents[4]
+	re := "^" + strings.Join(regexComponents, "") + "$"
 	matcher, err := regexp.Compile(re)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "gmobot: regex.Compile(): %s\n", err);
-		return masterWordList
+		return words
 	}
 
 	// Run the regex over the master word list
@@ -202,8 +237,22 @@ func choose(possible []string, letterFreqs []float32) string {
 }
 
 // Initialize the bot. Caller determines success or failure by checking
-// the top level variables we're supposed to set.
-func botInit(corpus []string) {
+// the bot variables we're supposed to set. The frequency list may
+// contain words of many lengths and words the engine won't accept; only
+// those in the list of allowed guesses are kept. Without a guess list
+// the engine accepts any word, so every word of the right length is kept.
+func (b *GmoBot) botInit(words *WordLists) {
+	allowed := make(map[string]bool, len(words.Guesses))
+	for _, w := range words.Guesses {
+		allowed[w] = true
+	}
+	keep := func(w string) bool {
+		if words.AnyGuess {
+			return len(w) == len(words.Answers[0])
+		}
+		return allowed[w]
+	}
+
 	// First load the word frequency list
 	for _, s := range os.Args {
 		if strings.HasPrefix(s, prefix) {
@@ -211,7 +260,11 @@ func botInit(corpus []string) {
 			if len(params) >= 1 {
 				wf, err := gtw.LoadFile(params[0])
 				if err == nil {
-					masterWordList = wf
+					for _, w := range wf {
+						if keep(w) {
+							b.masterWordList = append(b.masterWordList, w)
+						}
+					}
 				} else {
 					fmt.Fprintf(os.Stderr, "%s %s\n", prefix, err)
 				}
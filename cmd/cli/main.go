@@ -1,7 +1,7 @@
 /*
 Package cli implements a command line interface to play a word game.
 
-When given a file containing a corpus of 5-letter words, one per line,
+When given a file containing a corpus of words, one per line,
 allows the game to be played interactively from the command line. The
 interactive component produces output as described below. By default,
 the game core produces no output for guesses but produces a summary
@@ -9,6 +9,71 @@ at the end of its run. Use -v for more output. The primary purpose of
 the cli is to run a large number of games over a set of "guessers"
 (bots) built into the code. Use -h to see other command line options.
 
+All the words in the corpus must have the same length. The length is
+usually 5, but corpora of 4-, 6- or 7-letter words work the same way.
+
+Like the real game, the cli distinguishes the answer list (the corpus
+given with -c, from which goal words are chosen) from the guess list
+(given with -a, the words accepted as guesses). The answer list is
+usually small and the guess list large, for example:
+
+	cli -c wordle.corpus -a webster-2-all-five-letter.corpus -s ALL
+
+Without -a there is no guess list and any word of the right length is
+accepted as a guess, even one that isn't a word at all.
+
+A game is lost if the goal word isn't found within the turn limit, set
+with -turns, which is 6 guesses as in the real game. There is always a
+turn limit; -turns 0 means the default. The summary for each bot has
+these lines:
+
+	STATS    the mean number of guesses in the games won, and the number
+	         of games won in 0, 1, 2 ... guesses
+	WINS     the number of games won and the win rate
+	LOSSES   the number of games lost and their goal words
+	WORST    the most guesses needed to win a game, and its goal words
+	INVALID  the number of guesses rejected by the engine
+
+With -hardest N the summary also lists, on HARD lines, the N goal words
+for which each bot needed the most guesses, failures first, with the
+bot's guesses. With several bots it then lists the N goal words that
+were hardest for all of them, i.e. for which even the best bot needed
+the most guesses, with the mean number of guesses of all the bots (a
+loss counting as one more than the turn limit).
+
+When several bots are selected, each pair of bots is compared game by
+game on a COMPARE line. It shows the mean difference in the number of
+guesses (a loss counting as one more than the turn limit) with its 95%
+confidence interval, the number of games in which the first bot needed
+fewer, the same or more guesses, and the p-values of the paired t-test
+and the sign test. A negative difference with a small p-value means the
+first bot really is better on these goal words. The interval and the
+t-test need at least two games in which the differences vary; otherwise
+the line shows "no CI" and a p-value of n/a.
+
+With -format json or -format csv the cli writes a record for each game
+instead, and a summary record for each bot in place of the lines above.
+See output.go for the fields.
+
+With -log the cli writes every game it plays to a game log, recording
+the corpus, the seed and the rules, and each game's goal words, guesses
+and signatures. The replay command plays the games of a log again with
+the same corpus and allowed guesses, checks that every signature is the
+same, and prints the games:
+
+	cli -c wordle.corpus -a webster-2-all-five-letter.corpus -s ALL -log before.log
+	cli -c wordle.corpus -a webster-2-all-five-letter.corpus replay before.log
+
+Logs of the same games written before and after a change to a bot can be
+compared with diff to see where the bot's guesses changed.
+
+Bots can also be written in any language and run as separate processes,
+for example with -s "exec:python3 mybot.py". The harness talks to them
+over their standard input and output with the line protocol described
+in exec.go, and reports their results like those of the built-in bots.
+As -s is a comma-separated list, the command cannot contain a comma.
+A bot that stops answering for longer than -exectimeout is killed.
+
 Interactive component: after each incorrect guess, a signature will
 be displayed. In the signature, the character '-' means the letter
 is not in the word. Lower case letters are not in the right place,
@@ -22,6 +87,50 @@ guess> aural
        *URAL (4 letters in the correct place)
 guess> rural
 
+When the output is a terminal the guesses are drawn instead as colored
+tiles, followed by a keyboard showing what is known about each letter.
+Set NO_COLOR to get the plain output above.
+
+Instead of a guess the user can type one of these commands:
+
+	/hint     the number of words still possible, then a letter of the goal word
+	/history  show all the guesses again
+	/giveup   give up the game and show the goal word
+	/undo     take back the last guess, only in practice mode (-practice)
+
+At the end of the input the game is abandoned and no more games are
+played.
+
+With -boards N each game has N goal words, taken in turn from the goal
+words, and each guess is scored against every board not yet solved
+(this is known elsewhere as Dordle, Quordle or Octordle). The results
+for all the boards are shown side by side. A game is won when every
+board is solved, and the STATS line counts the guesses needed to clear
+all the boards.
+
+With -lie exactly one letter of every signature is wrong, except that
+a winning guess is always scored truthfully (this is known elsewhere as
+Fibble). The lies are chosen at random; use -seed to play the same lies
+again. The solver bots allow for the lie; gmobot does not.
+
+With -share emoji (or -share ascii) the ui strategy prints a grid of
+colored squares after each game for sharing the result in chat.
+
+With -daily the goal word is the word of the day, which is the same for
+everyone using the same corpus. Each day has a puzzle number, counted
+from 2022-01-01, which is shown in the share grid. Use -date to play
+the puzzle of another day.
+
+The ui strategy keeps the player's statistics, including streaks and the
+distribution of guesses, in a file in the user's config directory. They
+are kept separately for each corpus and mode, and printed after each game.
+Practice games and games abandoned at the end of the input don't count.
+
+With -assist the cli helps with a game played somewhere else. Enter each
+guess and the colours it was given, e.g. "crane g.y.." for a green c and
+a yellow a. The cli lists the answers still possible and the guesses the
+selected strategies would make next.
+
 */
 
 package main
@@ -29,38 +138,149 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gmofishsauce/gtw/lib"
 )
 
+// WordLists holds the words made available to a Guesser. Answers
+// is the list of possible goal words. Guesses is the list of words
+// the engine accepts as guesses; it contains every answer and is
+// often much larger. All the words have the same length, usually 5.
+// When no guess list is given (see -a) the engine accepts any word of
+// the right length; Guesses is then just the answer list and AnyGuess
+// is true.
+type WordLists struct {
+	Answers  []string
+	Guesses  []string
+	AnyGuess bool
+}
+
 // Guesser is the interface to be implemented by GTW bots. A Guesser
-// is passed a corpus of words, a history of past "scores" for this
+// is passed the word lists, a history of past "scores" for this
 // game, and the number of correct letters in the previous guess.
-// The corpus is a slice of 5-letter strings. The scores are a slice
-// of signatures, 5-letter strings in which "+" indicates a correct
+// The scores are a slice of signatures, strings of the same length
+// as the words in which "+" indicates a correct
 // letter, "#" indicates an incorrect letter, and "*" indicates a
 // letter in the word but not in the correct location. The bot can
 // deduce the start of a new game (i.e. new goal word) when the scores
 // slice is 0-length.
 type Guesser interface{
-	Guess(corpus []string, scores []string, nCorrect int) string
+	Guess(words *WordLists, scores []string, nCorrect int) string
 }
 
 // Golang allows functions to implement interfaces. This adapter with
 // the signature of a Guesser supports this, making it unnecessary to
-// create an object to implement the interface. See the "ui" strategy
-// in the strategies array below for an example
-type GuesserFunc func([]string, []string, int) string
+// create an object to implement the interface. See the "pathetic"
+// strategy in the strategies array below for an example
+type GuesserFunc func(*WordLists, []string, int) string
 
-func (f GuesserFunc) Guess(c []string, s []string, n int) string {
-	return f(c, s, n)
+func (f GuesserFunc) Guess(w *WordLists, s []string, n int) string {
+	return f(w, s, n)
 }
 
-// Each Guesser bot is defined by a Strategy instance.
+// Turn is one scored guess: the guess, its signature and the number
+// of correct letters in the guess.
+type Turn struct {
+	Guess     string
+	Signature string
+	NCorrect  int
+}
+
+// Bot is the richer interface to be implemented by GTW bots. The
+// harness calls NewGame at the start of each game, then Guess until
+// the game is over, then EndGame. Guess is passed the turns of the
+// current game so far, the bot's own scored guesses alongside their
+// signatures. A guess rejected by the engine does not appear in the
+// turns, so the bot is simply asked again. A bot gives up the game by
+// returning an empty guess. EndGame reports the goal word and the final
+// turns whether or not the bot found the goal.
+// Bots implementing the older Guesser interface can be used as Bots
+// through AdaptGuesser.
+type Bot interface {
+	NewGame(words *WordLists)
+	Guess(turns []Turn) string
+	EndGame(goal string, turns []Turn)
+}
+
+// Board is the state of one board of a multi-board game (see -boards):
+// the turns scored against the board, which stop when it is solved.
+type Board struct {
+	Turns  []Turn
+	Solved bool
+}
+
+// MultiBot is implemented by bots that can play several boards at once.
+// GuessBoards is passed every board and returns a guess, which is scored
+// against each unsolved board. EndBoards reports the goal word of each
+// board and the final boards. A Bot that isn't a MultiBot plays a
+// multi-board game one board at a time: it is asked to Guess for the
+// first unsolved board, and EndGame is called once for each board.
+type MultiBot interface {
+	Bot
+	GuessBoards(boards []Board) string
+	EndBoards(goals []string, boards []Board)
+}
+
+// GameAware is implemented by bots that need the game itself, such as
+// the ui strategy, whose commands give hints and take back guesses. The
+// harness calls SetGame with each new game before calling NewGame.
+type GameAware interface {
+	SetGame(game *gtw.MultiGame)
+}
+
+// closeBots releases whatever the bots hold once they have played all
+// their games. Bots holding resources, such as the process of an ExecBot,
+// implement io.Closer. A bot may be nil if it was never needed.
+func closeBots(bots []Bot) {
+	for _, bot := range bots {
+		if c, ok := bot.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// AdaptGuesser returns a Bot that plays using the Guesser.
+func AdaptGuesser(g Guesser) Bot {
+	return &guesserBot{g: g}
+}
+
+type guesserBot struct {
+	g     Guesser
+	words *WordLists
+}
+
+func (b *guesserBot) NewGame(words *WordLists) {
+	b.words = words
+}
+
+func (b *guesserBot) Guess(turns []Turn) string {
+	scores := make([]string, len(turns))
+	nCorrect := 0
+	for i, t := range turns {
+		scores[i] = t.Signature
+		nCorrect = t.NCorrect
+	}
+	return b.g.Guess(b.words, scores, nCorrect)
+}
+
+func (b *guesserBot) EndGame(goal string, turns []Turn) {
+}
+
+// Each bot is defined by a Strategy instance. The newBot
+// function creates a fresh instance of the bot. When games are run
+// in parallel each worker creates its own instances, so a bot that
+// keeps state between calls must keep it in the instance rather
+// than in package variables.
 type Strategy struct {
 	name string
-	bot  Guesser 
+	newBot func() Bot
 	interactive bool
 }
 
@@ -68,23 +288,50 @@ type Strategy struct {
 // convenience when constructing command lines. The bots "pathetic" and
 // "amazing" are intended for early testing and will be removed.
 var registeredStrategies = []Strategy {
-	Strategy{name: "gmobot", bot: GuesserFunc(GmoGuess), interactive: false},
-	Strategy{name: "ui", bot: GuesserFunc(UserGuess), interactive: true},
-	Strategy{name: "pathetic", bot: GuesserFunc(HopelessGuesser), interactive: false},
-	Strategy{name: "amazing", bot: GuesserFunc(AmazingGuesser), interactive: false},
+	Strategy{name: "gmobot", newBot: NewGmoBot, interactive: false},
+	Strategy{name: "ui", newBot: NewUserBot, interactive: true},
+	Strategy{name: "entropy", newBot: NewEntropyBot, interactive: false},
+	Strategy{name: "minimax", newBot: NewMinimaxBot, interactive: false},
+	Strategy{name: "minimaxall", newBot: NewMinimaxFullBot, interactive: false},
+	Strategy{name: "tree", newBot: NewTreeBot, interactive: false},
+	Strategy{name: "pathetic", newBot: func() Bot { return AdaptGuesser(GuesserFunc(HopelessGuesser)) }, interactive: false},
+	Strategy{name: "amazing", newBot: func() Bot { return AdaptGuesser(&AmazingGuesser{}) }, interactive: false},
 }
 
 // Command line flags
-var corpusPath = flag.String("c", "", "required: `corpus-file` to load")
+var corpusPath = flag.String("c", "", "required: `corpus-file` of possible answers to load")
 var nGames = flag.Int("n", 0, "the `number` of games to run, default entire corpus")
 var verbose = flag.Bool("v", false, "enable verbose output")
-var strategyNames = flag.String("s", "ui", "comma-separated list of `strategy-names` or ALL for all noninteractive strategies")
-var goals = flag.String("g", "", "list of `goal-words`, default entire corpus")
+var strategyNames = flag.String("s", "ui", "comma-separated list of `strategy-names` or ALL for all noninteractive strategies; exec:command runs an external bot (see exec.go), whose command cannot contain a comma")
+var goals = flag.String("g", "", "list of `goal-words` to play, default entire corpus")
+var allowedPath = flag.String("a", "", "`guess-list` file of allowed guesses; other guesses are rejected as invalid, default accept any word of the right length")
+var hardMode = flag.Bool("hard", false, "hard mode: every guess must reuse all revealed letters")
+var absurdle = flag.Bool("absurdle", false, "adversarial mode: the engine picks the goal word as late as possible")
+var jobs = flag.Int("j", 1, "run games in `N` parallel workers (noninteractive strategies only)")
+var lying = flag.Bool("lie", false, "lying mode: one letter of every signature but a win is wrong")
+var seed = flag.Int64("seed", -1, "`seed` for the random choices of the engine, such as the lies; default random")
+var share = flag.String("share", "", "after each game played by the ui strategy, print a share grid in `style` emoji or ascii")
+var daily = flag.Bool("daily", false, "play today's daily puzzle, the same for everyone using the same corpus")
+var date = flag.String("date", "", "play the daily puzzle of `YYYY-MM-DD` instead of today's")
+var statsPath = flag.String("stats", "", "`file` keeping the ui strategy's statistics between runs, default in the user's config directory")
+var assistMode = flag.Bool("assist", false, "assistant mode: enter the guesses and colours from another game and get advice")
+var practice = flag.Bool("practice", false, "practice mode: the ui strategy's /undo command takes back guesses")
+var nBoards = flag.Int("boards", 1, "play `N` goal words at once; each guess is scored against every unsolved board")
+var format = flag.String("format", "", "write a record of each game and a summary for each bot in `format` json or csv")
+var logPath = flag.String("log", "", "write every game played to the game log `file`, for the replay command")
+var hardest = flag.Int("hardest", 0, "list the `N` goal words each bot found hardest, and those hardest for all bots")
+var turnLimit = flag.Int("turns", 0, "the `number` of guesses allowed in a game, default 6, or 5+N with -boards N; there is no unlimited mode")
+var execTimeout = flag.Duration("exectimeout", time.Minute, "how long an external bot (-s exec:...) may take to reply, or to exit after its last game, before it is killed")
+
+// A bot that keeps making guesses the engine rejects loses the game after
+// this many, so it cannot loop forever.
+const MAX_INVALID = 20
 
-// This is used to size the slice that holds the distribution of results for each
-// bot, so enormous numbers are not advisable. It will work fine, but the output
-// will be ridiculously hard to read if there are stupid bots that make many guesses.
-const MAX_TRIES = 20
+// The number of the daily puzzle being played, or -1 if not in daily mode
+var dailyPuzzle = -1
+
+// The game log written with -log
+var gameLog *gtw.LogWriter
 
 func main() {
 	flag.Parse()
@@ -98,6 +345,11 @@ func main() {
 		fmt.Printf("Cannot load corpus: %s\n", err)
 		return
 	}
+	wordLength, err := gtw.CorpusWordLength(corpus)
+	if err != nil {
+		fmt.Printf("Bad corpus %s: %s\n", *corpusPath, err)
+		return
+	}
 
 	var selectedStrategies []Strategy
 	if *strategyNames == "ALL" {
@@ -107,10 +359,19 @@ func main() {
 			}
 		}
 	} else {
-		selectedNames := strings.Split(*strategyNames, ",")
-		for _, s := range(registeredStrategies) {
-			if stringInSlice(s.name, selectedNames) {
-				selectedStrategies = append(selectedStrategies, s)
+		// The bots are taken in the order they are named
+		for _, name := range strings.Split(*strategyNames, ",") {
+			if strings.HasPrefix(name, execPrefix) {
+				// External bots, see exec.go
+				command := strings.TrimPrefix(name, execPrefix)
+				newBot := func() Bot { return NewExecBot(command) }
+				selectedStrategies = append(selectedStrategies, Strategy{name: name, newBot: newBot, interactive: false})
+				continue
+			}
+			for _, s := range(registeredStrategies) {
+				if s.name == name {
+					selectedStrategies = append(selectedStrategies, s)
+				}
 			}
 		}
 	}
@@ -128,85 +389,549 @@ func main() {
 			fmt.Printf("Cannot load goal words from %s\n", *goals)
 			return
 		}
+		if n, err := gtw.CorpusWordLength(goalWords); err != nil || n != wordLength {
+			fmt.Printf("Goal words in %s must all have length %d\n", *goals, wordLength)
+			return
+		}
+	}
+	if *daily || *date != "" {
+		day := time.Now()
+		if *date != "" {
+			day, err = time.Parse("2006-01-02", *date)
+			if err != nil {
+				fmt.Printf("Bad date %s, expected YYYY-MM-DD\n", *date)
+				return
+			}
+		}
+		dailyPuzzle = gtw.PuzzleNumber(day)
+		goal, err := gtw.DailyGoal(corpus, dailyPuzzle)
+		if err != nil {
+			fmt.Printf("Cannot play the daily puzzle: %s\n", err)
+			return
+		}
+		goalWords = []string{goal}
+		if *verbose {
+			note("Daily puzzle %d of %s\n", dailyPuzzle, gtw.PuzzleDate(dailyPuzzle).Format("2006-01-02"))
+		}
 	}
 
+	if *share != "" && *share != "emoji" && *share != "ascii" {
+		fmt.Printf("The share style must be emoji or ascii\n")
+		return
+	}
+	if *nBoards < 1 || *nBoards > len(goalWords) {
+		fmt.Printf("The number of boards must be between 1 and the number of goal words (%d)\n", len(goalWords))
+		return
+	}
+	if *absurdle && *nBoards > 1 {
+		// The adversary ignores the goal words, so the boards would all be the same
+		fmt.Printf("The -absurdle mode cannot be played with more than one board\n")
+		return
+	}
+	if *format != "" && *format != "json" && *format != "csv" {
+		fmt.Printf("The format must be json or csv\n")
+		return
+	}
+	// The summaries count a loss as one more than the turn limit, so
+	// the cli always has one, though the engine can play without.
+	if *turnLimit < 0 {
+		fmt.Printf("The turn limit must be at least 1; there is no unlimited mode\n")
+		return
+	}
+	if *turnLimit == 0 {
+		// As in the real games: 6 guesses for 1 board, 9 for 4, 13 for 8
+		*turnLimit = 5 + *nBoards
+	}
 	games := *nGames
-	if games == 0 || games >= len(goalWords) {
-		games = len(goalWords)
+	if games == 0 && *absurdle {
+		// The adversary doesn't use the goal words, so every game
+		// played by a deterministic bot is the same.
+		games = 1
+	}
+	if games == 0 || games >= len(goalWords) / *nBoards {
+		games = len(goalWords) / *nBoards
+	}
+	// Each game takes the next nBoards goal words
+	gameGoals := make([][]string, games)
+	for i := range gameGoals {
+		gameGoals[i] = goalWords[i * *nBoards : (i+1) * *nBoards]
 	}
 	if *verbose {
-		fmt.Printf("Running %d games\n", games)
+		note("Running %d games of %d-letter words in %s mode\n", games, wordLength, modeName())
 	}
 
-	runAllSelectedBotsNGames(gtw.New(corpus), games, selectedStrategies, goalWords)
+	words := &WordLists{Answers: corpus, Guesses: corpus, AnyGuess: true}
+	engine := gtw.New(corpus)
+	engine.SetHardMode(*hardMode)
+	engine.SetAdversarial(*absurdle)
+	engine.SetLying(*lying)
+	engine.SetSeed(*seed)
+	engine.SetTurnLimit(*turnLimit)
+	if *allowedPath != "" {
+		allowed, err := gtw.LoadFile(*allowedPath)
+		if err != nil {
+			fmt.Printf("Cannot load allowed guesses: %s\n", err)
+			return
+		}
+		if n, err := gtw.CorpusWordLength(allowed); err != nil || n != wordLength {
+			fmt.Printf("Allowed guesses in %s must all have length %d\n", *allowedPath, wordLength)
+			return
+		}
+		words.Guesses = gtw.MergeWordLists(allowed, corpus)
+		words.AnyGuess = false
+		engine.SetAllowedGuesses(words.Guesses)
+	}
+	if *verbose {
+		note("%d possible answers, %d allowed guesses\n", len(words.Answers), len(words.Guesses))
+	}
+	if *assistMode {
+		assist(words, selectedStrategies)
+		return
+	}
+	if flag.Arg(0) == "replay" {
+		replay(engine, flag.Arg(1))
+		return
+	}
+	if *logPath != "" {
+		f, err := os.Create(*logPath)
+		if err != nil {
+			fmt.Printf("Cannot create game log: %s\n", err)
+			return
+		}
+		defer f.Close()
+		if gameLog, err = gtw.NewLogWriter(f, engine.LogHeader()); err != nil {
+			fmt.Printf("Cannot write game log: %s\n", err)
+			return
+		}
+	}
+	runAllSelectedBotsNGames(engine, words, selectedStrategies, gameGoals)
 }
 
-func runAllSelectedBotsNGames(engine *gtw.GtwEngine, games int, selectedStrategies []Strategy, goalWords []string) {
-	statistics := make(map[string][]int)
+// gameResult is the outcome of one bot playing one game.
+type gameResult struct {
+	goal       string // the goal words joined by "/", chosen late by the adversary in -absurdle mode
+	won        bool
+	turns      int // scored guesses, i.e. the guesses needed to clear all the boards
+	solved     int // boards solved
+	invalid    int // guesses rejected by the engine
+	guesses    []string
+	signatures []string // the signatures for each board joined by "/", "" for a solved board
+	elapsed    time.Duration
+	entry      gtw.LogEntry
+}
+
+// runAllSelectedBotsNGames plays each game, given by its goal words, with
+// every selected bot and prints a summary for each bot. With -j N the
+// (bot, game) pairs are spread over N workers, each having its own bot
+// instances. The results are merged in game order, so the output
+// doesn't depend on the number of workers.
+func runAllSelectedBotsNGames(engine *gtw.GtwEngine, words *WordLists, selectedStrategies []Strategy, gameGoals [][]string) {
+	games := len(gameGoals)
+	results := make([][]gameResult, len(selectedStrategies))
+	for k := range selectedStrategies {
+		results[k] = make([]gameResult, games)
+	}
 
+	workers := *jobs
 	for _, s := range selectedStrategies {
-		statistics[s.name] = make([]int, MAX_TRIES, MAX_TRIES)
-	}
-	
-	for i := 0; i < games; i++ {
-		engine.NewFixedGame(goalWords[i])
-		goal := engine.Cheat()
-		// fmt.Printf("cheat: \"%s\"\n", goal)
-
-		for _, s := range selectedStrategies {
-			var guessResults []string
-			nCorrect := 0
-			var signature string
-
-			for tries := 1; ; tries++ {
-				guess := s.bot.Guess(engine.Corpus(), guessResults, nCorrect)
-				signature, nCorrect = engine.Score(guess)
-				if nCorrect == 5 {
-					if *verbose {
-						fmt.Printf("PASS: bot \"%s\" goal %s n %d\n", s.name, goal, tries)
-					}
-					statistics[s.name][tries]++
-					break
+		if s.interactive {
+			workers = 1
+		}
+	}
+
+	if workers <= 1 {
+		// Report each game as soon as it's played
+		bots := make([]Bot, len(selectedStrategies))
+		for k, s := range selectedStrategies {
+			bots[k] = s.newBot()
+		}
+		for i := 0; i < games; i++ {
+			for k, s := range selectedStrategies {
+				results[k][i] = playGame(engine, words, s, bots[k], gameGoals[i])
+			}
+			if inputEnded {
+				// The ui's player has gone; the game they left
+				// unfinished isn't counted for any bot.
+				games = i
+				for k := range results {
+					results[k] = results[k][:games]
+				}
+				break
+			}
+			for k, s := range selectedStrategies {
+				reportGame(s, results[k][i])
+			}
+		}
+		closeBots(bots)
+	} else {
+		runParallel(engine, words, workers, selectedStrategies, gameGoals, results)
+		for i := 0; i < games; i++ {
+			for k, s := range selectedStrategies {
+				reportGame(s, results[k][i])
+			}
+		}
+	}
+
+	for k, s := range selectedStrategies {
+		summary := summarize(results[k])
+		if *format != "" {
+			writeSummaryRecord(s, summary)
+			continue
+		}
+		fmt.Printf("STATS bot %s : %4.2f (%v)\n", s.name, summary.meanGuesses, summary.counts)
+		fmt.Printf("WINS bot %s : %d of %d (%4.1f%%)\n", s.name, summary.wins, summary.games, summary.winRate)
+		fmt.Printf("LOSSES bot %s : %d (%s)\n", s.name, len(summary.losses), strings.Join(summary.losses, ","))
+		fmt.Printf("WORST bot %s : %d (%s)\n", s.name, summary.worst, strings.Join(summary.worstGoals, ","))
+		fmt.Printf("INVALID bot %s : %d\n", s.name, summary.invalid)
+		for _, i := range hardestGames(guessCounts(results[k]), *hardest) {
+			r := results[k][i]
+			outcome := fmt.Sprintf("%d", r.turns)
+			if !r.won {
+				outcome = "FAIL"
+			}
+			fmt.Printf("HARD bot %s : %s %s (%s)\n", s.name, r.goal, outcome, strings.Join(r.guesses, ","))
+		}
+	}
+	if *format == "" && *hardest > 0 && len(selectedStrategies) > 1 {
+		// A game is as hard for every bot as it was for the best of them
+		best := guessCounts(results[0])
+		total := make([]float64, games)
+		for k := range selectedStrategies {
+			for i, n := range guessCounts(results[k]) {
+				if n < best[i] {
+					best[i] = n
 				}
-				guessResults = append(guessResults, signature)	
-				if tries >= MAX_TRIES {
-					if *verbose {
-						fmt.Printf("FAIL: bot \"%s\" goal %s n %d\n", s.name, goal, tries)
-					}
-					statistics[s.name][MAX_TRIES-1]++
+				total[i] += n
+			}
+		}
+		for _, i := range hardestGames(best, *hardest) {
+			outcome := fmt.Sprintf("%.0f", best[i])
+			if best[i] > float64(*turnLimit) {
+				outcome = "FAIL"
+			}
+			fmt.Printf("HARD all bots : %s best %s mean %.2f\n", results[0][i].goal, outcome, total[i]/float64(len(selectedStrategies)))
+		}
+	}
+
+	// Every bot played the same games, so each pair of bots can be
+	// compared game by game.
+	for a := range selectedStrategies {
+		for b := a + 1; b < len(selectedStrategies); b++ {
+			c := gtw.ComparePaired(guessCounts(results[a]), guessCounts(results[b]))
+			if *format != "" {
+				writeComparisonRecord(selectedStrategies[a], selectedStrategies[b], c)
+				continue
+			}
+			ci, tTest := "no CI", "n/a"
+			if !math.IsNaN(c.CILow) {
+				ci = fmt.Sprintf("95%% CI %+.3f to %+.3f", c.CILow, c.CIHigh)
+			}
+			if !math.IsNaN(c.TTestP) {
+				tTest = fmt.Sprintf("%.3g", c.TTestP)
+			}
+			fmt.Printf("COMPARE bot %s vs %s : %+.3f (%s) better %d tie %d worse %d, t-test p %s, sign test p %.3g\n",
+				selectedStrategies[a].name, selectedStrategies[b].name, c.MeanDiff, ci,
+				c.Lower, c.Ties, c.Higher, tTest, c.SignTestP)
+		}
+	}
+}
+
+// hardestGames returns the indexes of the n games having the highest
+// guess counts, hardest first. Games with the same count are taken in
+// the order they were played.
+func hardestGames(counts []float64, n int) []int {
+	order := make([]int, len(counts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if n < len(order) {
+		order = order[:n]
+	}
+	return order
+}
+
+// guessCounts returns the number of guesses used in each game for the
+// comparison of bots, counting a loss as one more than the turn limit.
+func guessCounts(results []gameResult) []float64 {
+	counts := make([]float64, len(results))
+	for i, r := range results {
+		if r.won {
+			counts[i] = float64(r.turns)
+		} else {
+			counts[i] = float64(*turnLimit + 1)
+		}
+	}
+	return counts
+}
+
+// botSummary is the summary of the games played by one bot.
+type botSummary struct {
+	games       int
+	wins        int
+	winRate     float64 // percent
+	meanGuesses float64 // in the games won
+	counts      []int   // counts[n] is the number of games won in n guesses
+	losses      []string
+	worst       int
+	worstGoals  []string
+	invalid     int
+	elapsed     time.Duration
+}
+
+// summarize returns the summary of the results of one bot.
+func summarize(results []gameResult) botSummary {
+	summary := botSummary{games: len(results), counts: make([]int, *turnLimit+1)}
+	sum := 0
+	for _, r := range results {
+		if r.won {
+			summary.counts[r.turns]++
+			summary.wins++
+			sum += r.turns
+		} else {
+			summary.losses = append(summary.losses, r.goal)
+		}
+		summary.invalid += r.invalid
+		summary.elapsed += r.elapsed
+	}
+	if summary.wins > 0 {
+		summary.meanGuesses = float64(sum) / float64(summary.wins)
+	}
+	if summary.games > 0 {
+		summary.winRate = 100 * float64(summary.wins) / float64(summary.games)
+	}
+	summary.worst, summary.worstGoals = worstCase(results)
+	return summary
+}
+
+// worstCase returns the largest number of guesses the bot needed for any
+// goal word it found and the goal words for which it needed that many.
+// Losses are reported separately. At most five goal words are returned.
+func worstCase(results []gameResult) (int, []string) {
+	worst := 0
+	var worstGoals []string
+	for _, r := range results {
+		if !r.won {
+			continue
+		}
+		n := r.turns
+		if n > worst {
+			worst = n
+			worstGoals = worstGoals[:0]
+		}
+		if n == worst && len(worstGoals) < 5 {
+			worstGoals = append(worstGoals, r.goal)
+		}
+	}
+	return worst, worstGoals
+}
+
+// runParallel plays every (bot, game) pair using a pool of workers and
+// stores the outcome in results[bot][game]. Each worker creates its own
+// instance of each bot the first time it needs one.
+func runParallel(engine *gtw.GtwEngine, words *WordLists, workers int, selectedStrategies []Strategy, gameGoals [][]string, results [][]gameResult) {
+	type job struct {
+		goal     int
+		strategy int
+	}
+	work := make(chan job)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bots := make([]Bot, len(selectedStrategies))
+			for j := range work {
+				s := selectedStrategies[j.strategy]
+				if bots[j.strategy] == nil {
+					bots[j.strategy] = s.newBot()
+				}
+				results[j.strategy][j.goal] = playGame(engine, words, s, bots[j.strategy], gameGoals[j.goal])
+			}
+			closeBots(bots)
+		}()
+	}
+
+	for i := range gameGoals {
+		for k := range selectedStrategies {
+			work <- job{goal: i, strategy: k}
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// playGame plays one game of the bot against the goal words, one board
+// for each goal word.
+func playGame(engine *gtw.GtwEngine, words *WordLists, s Strategy, bot Bot, goals []string) gameResult {
+	// Each game holds its own history, used to enforce hard mode
+	// and the turn limit.
+	game, err := engine.StartMultiGame(goals)
+	if err != nil {
+		fmt.Printf("Cannot start game: %s\n", err)
+		return gameResult{goal: strings.Join(goals, "/")}
+	}
+	start := time.Now()
+	multi, isMulti := bot.(MultiBot)
+	isMulti = isMulti && len(goals) > 1
+	invalid := 0
+
+	if aware, ok := bot.(GameAware); ok {
+		aware.SetGame(game)
+	}
+	bot.NewGame(words)
+	for !game.Over() {
+		boards := gameBoards(game)
+		var guess string
+		if isMulti {
+			guess = multi.GuessBoards(boards)
+		} else {
+			for _, b := range boards {
+				if !b.Solved {
+					guess = bot.Guess(b.Turns)
 					break
 				}
 			}
 		}
+		if guess == "" {
+			break // the bot gave up
+		}
+		_, err := game.Score(guess)
+		if err != nil {
+			// An invalid guess is not a try and has no signature.
+			// The bot is asked again with the same results, up to
+			// a limit so a stubborn bot cannot loop forever.
+			invalid++
+			if *verbose || s.interactive {
+				note("INVALID: bot \"%s\" goal %s guess %s: %s\n", s.name, strings.Join(goals, "/"), guess, err)
+			}
+			if invalid >= MAX_INVALID {
+				break
+			}
+		}
+	}
+	boards := gameBoards(game)
+	if isMulti {
+		multi.EndBoards(game.Goals(), boards)
+	} else {
+		for i, goal := range game.Goals() {
+			bot.EndGame(goal, boards[i].Turns)
+		}
 	}
-	for name, counts := range statistics {
-		sum := 0
-		for i, _ := range(counts) {
-			sum += i * counts[i]
+	entry := gtw.NewLogEntry(s.name, game)
+	signatures := make([]string, len(entry.Signatures))
+	for t, boards := range entry.Signatures {
+		signatures[t] = strings.Join(boards, "/")
+	}
+	return gameResult{
+		goal:       strings.Join(game.Goals(), "/"),
+		won:        game.Won(),
+		turns:      game.Turns(),
+		solved:     game.Solved(),
+		invalid:    invalid,
+		guesses:    entry.Guesses,
+		signatures: signatures,
+		elapsed:    time.Since(start),
+		entry:      entry,
+	}
+}
+
+// gameBoards returns the boards of the game with their scored guesses
+// as Turns.
+func gameBoards(game *gtw.MultiGame) []Board {
+	var boards []Board
+	for _, b := range game.Boards() {
+		boards = append(boards, Board{Turns: gameTurns(b), Solved: b.Won()})
+	}
+	return boards
+}
+
+// gameTurns returns the scored guesses of the game as Turns.
+func gameTurns(game *gtw.Game) []Turn {
+	guesses := game.Guesses()
+	signatures := game.Signatures()
+	turns := make([]Turn, len(guesses))
+	for i := range guesses {
+		turns[i] = Turn{
+			Guess:     guesses[i],
+			Signature: signatures[i],
+			NCorrect:  strings.Count(signatures[i], string(gtw.LETTER_CORRECT)),
 		}
-		score := float32(sum) / float32(games)
-		fmt.Printf("STATS bot %s : %4.2f (%v)\n", name, score, statistics[name])
 	}
+	return turns
 }
 
-func stringInSlice(s string, slice []string) bool {
-	for _, in := range slice {
-		if s == in {
-			return true
+// reportGame prints the outcome of a game in verbose mode, or writes
+// its record with -format. It also writes the game to the -log file.
+func reportGame(s Strategy, r gameResult) {
+	if gameLog != nil {
+		if err := gameLog.Write(r.entry); err != nil {
+			fmt.Printf("Cannot write game log: %s\n", err)
 		}
 	}
-	return false
+	if *format != "" {
+		writeGameRecord(s, r)
+		return
+	}
+	if !*verbose {
+		return
+	}
+	goal := r.goal
+	if r.won {
+		fmt.Printf("PASS: bot \"%s\" goal %s n %d\n", s.name, goal, r.turns)
+	} else {
+		boards := ""
+		if *nBoards > 1 {
+			boards = fmt.Sprintf(", %d of %d boards solved", r.solved, *nBoards)
+		}
+		fmt.Printf("FAIL: bot \"%s\" goal %s n %d (%d invalid guesses%s)\n", s.name, goal, r.turns, r.invalid, boards)
+	}
+}
+
+// modeName describes the game rules selected by the command line.
+func modeName() string {
+	var modes []string
+	if *hardMode {
+		modes = append(modes, "hard")
+	}
+	if *absurdle {
+		modes = append(modes, "adversarial")
+	}
+	if *lying {
+		modes = append(modes, "lying")
+	}
+	if dailyPuzzle >= 0 {
+		modes = append(modes, "daily")
+	}
+	if *practice {
+		modes = append(modes, "practice")
+	}
+	if *nBoards > 1 {
+		modes = append(modes, fmt.Sprintf("%d-board", *nBoards))
+	}
+	if len(modes) == 0 {
+		return "normal"
+	}
+	return strings.Join(modes, " ")
 }
 
 // --- For test purposes - won't leave permanently ---
-func HopelessGuesser(corpus []string, results []string, nCorrect int) string {
+func HopelessGuesser(words *WordLists, results []string, nCorrect int) string {
 	return "xvqzw"
 }
 
-var amazingGuesserMagic int
-func AmazingGuesser(corpus []string, results []string, nCorrect int) string {
-	result := corpus[amazingGuesserMagic]
-	amazingGuesserMagic++
+// AmazingGuesser only works when the goal words are the answer list
+// played in order by a single worker.
+type AmazingGuesser struct {
+	magic int
+}
+
+func (a *AmazingGuesser) Guess(words *WordLists, results []string, nCorrect int) string {
+	result := words.Answers[a.magic%len(words.Answers)]
+	a.magic++
 	return result
 }
 // --- End "for test purposes" ---
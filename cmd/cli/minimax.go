@@ -0,0 +1,29 @@
+package main
+
+// NewMinimaxBot returns the "minimax" strategy. Each guess is chosen to
+// minimize the size of the largest set of answers that could remain after
+// it is scored, i.e. to do as well as possible in the worst case. Guesses
+// are taken only from the answers still possible. See SolverBot.
+func NewMinimaxBot() Bot {
+	return &SolverBot{goodness: minimax, fullList: false}
+}
+
+// NewMinimaxFullBot returns the "minimaxall" strategy, which is the same
+// as "minimax" except that it guesses from the full guess list.
+func NewMinimaxFullBot() Bot {
+	return &SolverBot{goodness: minimax, fullList: true}
+}
+
+// minimax returns the negated size of the largest bucket, so that the
+// best guess has the highest value. Among guesses with the same largest
+// bucket, those producing more distinct signatures are preferred; the
+// adjustment for this is always less than 1.
+func minimax(buckets map[string]int, total int) float64 {
+	largest := 0
+	for _, n := range buckets {
+		if n > largest {
+			largest = n
+		}
+	}
+	return -float64(largest) + float64(len(buckets))/float64(total+1)
+}
@@ -0,0 +1,206 @@
+package main
+
+// Machine-readable output for -format json and -format csv. Each game
+// produces a "game" record, each bot a "summary" record and each pair of
+// bots a "comparison" record, in the same order as the text output. JSON
+// records are written one per line. CSV records share a single header;
+// the fields that don't apply to a record are left empty.
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+// gameRecord is the record of one game. Multi-board games have their
+// goal words and each of their signatures joined by "/".
+type gameRecord struct {
+	Record     string   `json:"record"` // "game"
+	Bot        string   `json:"bot"`
+	Goal       string   `json:"goal"`
+	Guesses    []string `json:"guesses"`
+	Signatures []string `json:"signatures"`
+	Outcome    string   `json:"outcome"` // "won" or "lost"
+	Turns      int      `json:"turns"`
+	Invalid    int      `json:"invalid"`
+	WallMs     float64  `json:"wallMs"`
+}
+
+// summaryRecord is the summary of the games played by one bot.
+type summaryRecord struct {
+	Record      string   `json:"record"` // "summary"
+	Bot         string   `json:"bot"`
+	Games       int      `json:"games"`
+	Wins        int      `json:"wins"`
+	WinRate     float64  `json:"winRate"`     // percent
+	MeanGuesses float64  `json:"meanGuesses"` // in the games won
+	Losses      []string `json:"losses"`
+	Worst       int      `json:"worst"` // the most guesses needed to win a game
+	Invalid     int      `json:"invalid"`
+	WallMs      float64  `json:"wallMs"`
+}
+
+// comparisonRecord is the comparison of two bots (see the COMPARE line
+// in main.go). Differences are the first bot's guesses minus the other's.
+// The confidence interval and the t-test's p-value are null (empty in CSV)
+// when they cannot be estimated.
+type comparisonRecord struct {
+	Record    string   `json:"record"` // "comparison"
+	Bot       string   `json:"bot"`
+	Other     string   `json:"other"`
+	Games     int      `json:"games"`
+	MeanDiff  float64  `json:"meanDiff"`
+	CILow     *float64 `json:"ciLow"`
+	CIHigh    *float64 `json:"ciHigh"`
+	Better    int      `json:"better"`
+	Ties      int      `json:"ties"`
+	Worse     int      `json:"worse"`
+	TTestP    *float64 `json:"tTestP"`
+	SignTestP float64  `json:"signTestP"`
+}
+
+var csvHeader = []string{"record", "bot", "goal", "guesses", "signatures", "outcome",
+	"games", "wins", "winRate", "meanGuesses", "losses", "turns", "worst", "invalid", "wallMs",
+	"other", "meanDiff", "ciLow", "ciHigh", "better", "ties", "worse", "tTestP", "signTestP"}
+
+var csvOut *csv.Writer
+
+func writeGameRecord(s Strategy, r gameResult) {
+	outcome := "lost"
+	if r.won {
+		outcome = "won"
+	}
+	record := gameRecord{
+		Record:     "game",
+		Bot:        s.name,
+		Goal:       r.goal,
+		Guesses:    r.guesses,
+		Signatures: r.signatures,
+		Outcome:    outcome,
+		Turns:      r.turns,
+		Invalid:    r.invalid,
+		WallMs:     milliseconds(r.elapsed),
+	}
+	if *format == "json" {
+		writeJSON(record)
+		return
+	}
+	writeCSV([]string{record.Record, record.Bot, record.Goal,
+		strings.Join(record.Guesses, " "), strings.Join(record.Signatures, " "), record.Outcome,
+		"", "", "", "", "",
+		strconv.Itoa(record.Turns), "", strconv.Itoa(record.Invalid), formatFloat(record.WallMs),
+		"", "", "", "", "", "", "", "", ""})
+}
+
+func writeSummaryRecord(s Strategy, summary botSummary) {
+	record := summaryRecord{
+		Record:      "summary",
+		Bot:         s.name,
+		Games:       summary.games,
+		Wins:        summary.wins,
+		WinRate:     summary.winRate,
+		MeanGuesses: summary.meanGuesses,
+		Losses:      summary.losses,
+		Worst:       summary.worst,
+		Invalid:     summary.invalid,
+		WallMs:      milliseconds(summary.elapsed),
+	}
+	if record.Losses == nil {
+		record.Losses = []string{}
+	}
+	if *format == "json" {
+		writeJSON(record)
+		return
+	}
+	writeCSV([]string{record.Record, record.Bot, "", "", "", "",
+		strconv.Itoa(record.Games), strconv.Itoa(record.Wins), formatFloat(record.WinRate),
+		formatFloat(record.MeanGuesses), strings.Join(record.Losses, " "),
+		"", strconv.Itoa(record.Worst), strconv.Itoa(record.Invalid), formatFloat(record.WallMs),
+		"", "", "", "", "", "", "", "", ""})
+}
+
+func writeComparisonRecord(a Strategy, b Strategy, c gtw.PairedComparison) {
+	record := comparisonRecord{
+		Record:    "comparison",
+		Bot:       a.name,
+		Other:     b.name,
+		Games:     c.N,
+		MeanDiff:  c.MeanDiff,
+		CILow:     optional(c.CILow),
+		CIHigh:    optional(c.CIHigh),
+		Better:    c.Lower,
+		Ties:      c.Ties,
+		Worse:     c.Higher,
+		TTestP:    optional(c.TTestP),
+		SignTestP: c.SignTestP,
+	}
+	if *format == "json" {
+		writeJSON(record)
+		return
+	}
+	writeCSV([]string{record.Record, record.Bot, "", "", "", "",
+		strconv.Itoa(record.Games), "", "", "", "", "", "", "", "",
+		record.Other, formatFloat(record.MeanDiff), formatOptional(record.CILow), formatOptional(record.CIHigh),
+		strconv.Itoa(record.Better), strconv.Itoa(record.Ties), strconv.Itoa(record.Worse),
+		formatOptional(record.TTestP), formatFloat(record.SignTestP)})
+}
+
+// note prints a line of verbose output. With -format it goes to standard
+// error, so that standard output holds only records.
+func note(message string, args ...interface{}) {
+	if *format != "" {
+		fmt.Fprintf(os.Stderr, message, args...)
+		return
+	}
+	fmt.Printf(message, args...)
+}
+
+// writeJSON writes a record as a line of JSON.
+func writeJSON(record interface{}) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write record: %s\n", err)
+		return
+	}
+	fmt.Printf("%s\n", data)
+}
+
+// writeCSV writes a CSV record, preceded by the header the first time.
+func writeCSV(fields []string) {
+	if csvOut == nil {
+		csvOut = csv.NewWriter(os.Stdout)
+		csvOut.Write(csvHeader)
+	}
+	csvOut.Write(fields)
+	csvOut.Flush()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// optional returns nil for NaN, which JSON cannot represent.
+func optional(f float64) *float64 {
+	if math.IsNaN(f) {
+		return nil
+	}
+	return &f
+}
+
+func formatOptional(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
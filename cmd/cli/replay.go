@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+// replay plays the games of a game log again with the engine, which must
+// have the corpus and allowed guesses the log was written with, and
+// prints each game with its signatures humanized.
+func replay(engine *gtw.GtwEngine, path string) {
+	if path == "" {
+		fmt.Printf("Usage: cli -c corpus-file [-a guess-list] replay log-file\n")
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("Cannot open game log: %s\n", err)
+		return
+	}
+	header, entries, err := gtw.ReadLog(f)
+	f.Close()
+	if err != nil {
+		fmt.Printf("Cannot read game log %s: %s\n", path, err)
+		return
+	}
+	if err := engine.ApplyLogHeader(header); err != nil {
+		fmt.Printf("Cannot replay %s: %s\n", path, err)
+		return
+	}
+
+	differ := 0
+	for n, entry := range entries {
+		fmt.Printf("game %d bot %s goal %s\n", n+1, entry.Bot, strings.Join(entry.Goals, "/"))
+		game, err := engine.Replay(entry)
+		if game != nil {
+			// Print the game as the engine replayed it, so a guess
+			// whose signature differs from the log shows the new one
+			boards := gameBoards(game)
+			for t := 0; t < game.Turns(); t++ {
+				guess := entry.Guesses[t]
+				columns := make([]string, len(boards))
+				for i, b := range boards {
+					if t < len(b.Turns) {
+						columns[i] = gtw.Humanize(b.Turns[t].Signature, guess)
+					} else {
+						columns[i] = strings.Repeat(" ", len(guess))
+					}
+				}
+				fmt.Printf("       %s  %s\n", guess, strings.Join(columns, "  "))
+			}
+		}
+		if err != nil {
+			fmt.Printf("MISMATCH: %s\n", err)
+			differ++
+		} else if game.Won() {
+			fmt.Printf("OK: won in %d guesses\n", game.Turns())
+		} else {
+			fmt.Printf("OK: lost after %d guesses\n", game.Turns())
+		}
+	}
+	fmt.Printf("Replayed %d games: %d match, %d differ\n", len(entries), len(entries)-differ, differ)
+}
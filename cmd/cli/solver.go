@@ -0,0 +1,237 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+// SolverBot is a bot that chooses each guess by partitioning the answers
+// that are still possible according to the signature the guess would
+// produce for each of them, and scoring the partition with a goodness
+// function. Signatures are computed by gtw.Signature, so repeated letters
+// are treated exactly as the engine treats them.
+//
+// If fullList is set the bot guesses from the full guess list, otherwise
+// only from the remaining answers. In hard mode the full list is limited
+// to the guesses the rules allow. If a guess is nevertheless rejected the
+// bot guesses only from the remaining answers for the rest of the game.
+// The choice depends only on the turns so far, so it is remembered and
+// reused in later games; this makes the expensive early guesses cheap
+// after the first game.
+//
+// In a multi-board game the bot guesses any board that has only one
+// candidate left. Otherwise it guesses from the candidates of all the
+// unsolved boards, choosing the guess with the highest total goodness.
+// In hard mode a guess must satisfy the rules of every board, and the
+// bot gives up if the boards' rules contradict each other.
+type SolverBot struct {
+	goodness       func(buckets map[string]int, total int) float64
+	fullList       bool
+	words          *WordLists
+	memo           map[string]string
+	lastTurns      int  // number of turns at the previous call to Guess
+	onlyCandidates bool // guess only words that could be the goal
+}
+
+func (b *SolverBot) NewGame(words *WordLists) {
+	if b.words != words {
+		b.words = words
+		b.memo = make(map[string]string)
+	}
+	b.lastTurns = -1
+	b.onlyCandidates = false
+}
+
+func (b *SolverBot) Guess(turns []Turn) string {
+	if len(turns) == b.lastTurns {
+		b.onlyCandidates = true
+	}
+	b.lastTurns = len(turns)
+	return b.choose(turns)
+}
+
+// choose returns the best guess for a single board.
+func (b *SolverBot) choose(turns []Turn) string {
+	key := historyKey(turns, b.onlyCandidates)
+	if guess, ok := b.memo[key]; ok {
+		return guess
+	}
+
+	candidates := remainingCandidates(b.words, turns)
+	var guess string
+	if len(candidates) <= 2 || b.onlyCandidates || !b.fullList {
+		guess = bestGuess(candidates, candidates, b.goodness)
+	} else {
+		guess = bestGuess(hardModeGuesses(b.words.Guesses, turns), candidates, b.goodness)
+	}
+	b.memo[key] = guess
+	return guess
+}
+
+func (b *SolverBot) EndGame(goal string, turns []Turn) {
+}
+
+func (b *SolverBot) GuessBoards(boards []Board) string {
+	turns := 0
+	var unsolved []Board
+	for _, board := range boards {
+		if len(board.Turns) > turns {
+			turns = len(board.Turns)
+		}
+		if !board.Solved {
+			unsolved = append(unsolved, board)
+		}
+	}
+	if turns == b.lastTurns {
+		b.onlyCandidates = true
+	}
+	b.lastTurns = turns
+
+	// At the start of the game all the boards look the same
+	sameHistory := true
+	for _, board := range unsolved {
+		if historyKey(board.Turns, false) != historyKey(unsolved[0].Turns, false) {
+			sameHistory = false
+		}
+	}
+	if sameHistory {
+		return b.choose(unsolved[0].Turns)
+	}
+
+	// In hard mode a guess must satisfy the rules of every board
+	allowed := func(pool []string) []string {
+		for _, board := range unsolved {
+			pool = hardModeGuesses(pool, board.Turns)
+		}
+		return pool
+	}
+	candidates := make([][]string, len(unsolved))
+	var pool []string
+	for i, board := range unsolved {
+		candidates[i] = remainingCandidates(b.words, board.Turns)
+		pool = append(pool, candidates[i]...)
+	}
+	for _, c := range candidates {
+		if len(c) == 1 && len(allowed(c)) == 1 {
+			return c[0]
+		}
+	}
+	// Guessing from the full list is too slow when there are several
+	// boards, so the pool is the candidates of every board, unless in
+	// hard mode none of them is allowed.
+	pool = allowed(gtw.MergeWordLists(pool, nil))
+	if len(pool) == 0 {
+		pool = allowed(b.words.Guesses)
+		if len(pool) == 0 {
+			return "" // the boards' rules contradict each other
+		}
+	}
+	best := ""
+	bestValue := math.Inf(-1)
+	for _, g := range pool {
+		value := 0.0
+		for _, c := range candidates {
+			value += b.goodness(partition(g, c), len(c))
+		}
+		if value > bestValue {
+			best = g
+			bestValue = value
+		}
+	}
+	return best
+}
+
+func (b *SolverBot) EndBoards(goals []string, boards []Board) {
+}
+
+// remainingCandidates returns the answers consistent with all the turns,
+// allowing for one lie in each signature in -lie mode. If no answer is
+// consistent, e.g. because the goal word isn't in the answer list, the
+// allowed guesses are tried instead.
+func remainingCandidates(words *WordLists, turns []Turn) []string {
+	for _, list := range [][]string{words.Answers, words.Guesses} {
+		candidates := list
+		for _, t := range turns {
+			if *lying {
+				candidates = gtw.RemainingWithLie(candidates, t.Guess, t.Signature)
+			} else {
+				candidates = gtw.Remaining(candidates, t.Guess, t.Signature)
+			}
+		}
+		if len(candidates) > 0 {
+			return candidates
+		}
+	}
+	return words.Guesses[:1]
+}
+
+// hardModeGuesses returns the words of the pool that the engine accepts
+// after the turns in hard mode, or the whole pool if not in hard mode.
+// Hard mode isn't enforced in lying mode (see gtw.Game.Score).
+func hardModeGuesses(pool []string, turns []Turn) []string {
+	if !*hardMode || *lying || len(turns) == 0 {
+		return pool
+	}
+	guesses := make([]string, len(turns))
+	signatures := make([]string, len(turns))
+	for i, t := range turns {
+		guesses[i] = t.Guess
+		signatures[i] = t.Signature
+	}
+	var allowed []string
+	for _, w := range pool {
+		if gtw.CheckHardMode(w, guesses, signatures) == nil {
+			allowed = append(allowed, w)
+		}
+	}
+	return allowed
+}
+
+// partition returns the number of candidates that would produce each
+// signature if the guess were scored against them.
+func partition(guess string, candidates []string) map[string]int {
+	buckets := make(map[string]int)
+	for _, c := range candidates {
+		signature, _ := gtw.Signature(guess, c)
+		buckets[signature]++
+	}
+	return buckets
+}
+
+// bestGuess returns the word from the pool having the highest value
+// of the goodness function applied to its partition of the candidates.
+// Ties go to words that are themselves candidates, because they might
+// win outright, and then to the earliest word in the pool.
+func bestGuess(pool []string, candidates []string, goodness func(map[string]int, int) float64) string {
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		isCandidate[c] = true
+	}
+	best := ""
+	bestValue := math.Inf(-1)
+	bestIsCandidate := false
+	for _, g := range pool {
+		value := goodness(partition(g, candidates), len(candidates))
+		if value > bestValue || (value == bestValue && isCandidate[g] && !bestIsCandidate) {
+			best = g
+			bestValue = value
+			bestIsCandidate = isCandidate[g]
+		}
+	}
+	return best
+}
+
+// historyKey returns a string that identifies the turns of a game.
+func historyKey(turns []Turn, onlyCandidates bool) string {
+	var key strings.Builder
+	if onlyCandidates {
+		key.WriteString("!")
+	}
+	for _, t := range turns {
+		key.WriteString(t.Guess)
+		key.WriteString(t.Signature)
+	}
+	return key.String()
+}
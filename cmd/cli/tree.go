@@ -0,0 +1,81 @@
+package main
+
+// GTW bot that replays a decision tree written by treegen. Usage:
+// ./cli -c wordle.corpus -s tree -- tree:wordle.tree
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+const treePrefix = "tree:"
+
+// TreeBot is the "tree" strategy. It makes the guesses recorded in the
+// tree. If the game leaves the tree, e.g. because the goal word was not
+// in the corpus the tree was built for, it guesses the first answer
+// that is still possible. It does the same when the tree's guess breaks
+// the hard mode rules, and for the rest of the game once the engine
+// rejects a guess from the tree, as can happen with a different guess
+// list from the one the tree was built with.
+type TreeBot struct {
+	tree      *gtw.DecisionTree
+	words     *WordLists
+	lastTurns int  // number of turns at the previous call to Guess
+	offTree   bool // a guess from the tree was rejected in this game
+}
+
+func NewTreeBot() Bot {
+	b := &TreeBot{}
+	for _, s := range os.Args {
+		if strings.HasPrefix(s, treePrefix) {
+			f, err := os.Open(strings.TrimPrefix(s, treePrefix))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s\n", treePrefix, err)
+				break
+			}
+			b.tree, err = gtw.ReadTree(f)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s\n", treePrefix, err)
+			}
+			break
+		}
+	}
+	if b.tree == nil {
+		fmt.Fprintf(os.Stderr, "tree: no tree loaded, use tree:<tree-file>\n")
+	}
+	return b
+}
+
+func (b *TreeBot) NewGame(words *WordLists) {
+	b.words = words
+	b.lastTurns = -1
+	b.offTree = false
+}
+
+func (b *TreeBot) Guess(turns []Turn) string {
+	if len(turns) == b.lastTurns {
+		b.offTree = true
+	}
+	b.lastTurns = len(turns)
+	if b.tree != nil && !b.offTree {
+		guesses := make([]string, len(turns))
+		signatures := make([]string, len(turns))
+		for i, t := range turns {
+			guesses[i] = t.Guess
+			signatures[i] = t.Signature
+		}
+		// A tree built without hard mode may break its rules
+		guess, ok := b.tree.Next(guesses, signatures)
+		if ok && len(hardModeGuesses([]string{guess}, turns)) == 1 {
+			return guess
+		}
+	}
+	return remainingCandidates(b.words, turns)[0]
+}
+
+func (b *TreeBot) EndGame(goal string, turns []Turn) {
+}
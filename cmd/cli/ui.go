@@ -10,29 +10,393 @@ import (
 )
 
 var console *bufio.Reader
-var previousGuess string
 
-func UserGuess(corpus []string, scores []string, nCorrect int) string {
+// inputEnded is set when the console input ends during a game. The
+// harness then stops running games rather than have the ui give up
+// every one that is left.
+var inputEnded bool
+
+// UserBot is the "ui" strategy. It lets a human play the game
+// from the console. When the console is a terminal the guesses are
+// drawn as colored tiles, with a keyboard showing what is known about
+// each letter; otherwise they are shown by gtw.Humanize.
+//
+// Besides guesses the user can type the commands /hint, /history,
+// /giveup and, in practice mode, /undo.
+type UserBot struct {
+	answers    []string
+	wordLength int
+	reported   int // number of turns reported to the user
+	color      bool
+	game       *gtw.MultiGame
+	words      *WordLists
+	hints      int      // number of hints given in this game
+	revealed   [][]bool // positions revealed by hints, for each board
+	abandoned  bool     // the input ended during this game
+}
+
+func NewUserBot() Bot {
+	return &UserBot{color: colorTerminal()}
+}
+
+func (u *UserBot) NewGame(words *WordLists) {
 	if console == nil {
 		console = bufio.NewReader(os.Stdin)
 	}
+	u.answers = words.Answers
+	u.words = words
+	u.wordLength = len(words.Answers[0])
+	u.reported = 0
+	u.hints = 0
+	u.revealed = nil
+	u.abandoned = false
+	fmt.Println("New goal word selected")
+}
 
-	if len(scores) == 0 { // new game
-		fmt.Println("New goal word selected")
-	} else {
-		// Not a new game - report the results of the user's previous guess
-		score := scores[len(scores) - 1]
-		fmt.Printf("       %s (%d letters in the correct place)\n", gtw.Humanize(score, previousGuess), nCorrect)
+func (u *UserBot) SetGame(game *gtw.MultiGame) {
+	u.game = game
+}
+
+func (u *UserBot) Guess(turns []Turn) string {
+	// Report the results of the user's previous guess. If the previous
+	// guess was rejected there is no new turn to report.
+	u.report(turns)
+	if u.color && len(turns) > 0 {
+		fmt.Printf("\n%s\n", keyboard(turns))
 	}
+	return u.read()
+}
 
+// read reads a guess of the right length from the console, carrying
+// out any commands typed first. It returns "" to give up the game, which
+// also happens at the end of the input.
+func (u *UserBot) read() string {
 	for { // loop over illegal guesses
 		fmt.Printf("guess> ")
-		text, _ := console.ReadString('\n')
+		text, err := console.ReadString('\n')
 		text = strings.TrimSpace(text)
-		if len(text) == 5 {
-			previousGuess = text
-			return previousGuess
+		if text == "" && err != nil {
+			fmt.Println()
+			u.abandoned = true
+			inputEnded = true
+			return ""
+		}
+		if strings.HasPrefix(text, "/") {
+			if text == "/giveup" {
+				return ""
+			}
+			u.command(text)
+			continue
+		}
+		if len(text) == u.wordLength {
+			return text
+		}
+		commands := "/hint, /history, /giveup"
+		if *practice {
+			commands += ", /undo"
+		}
+		fmt.Printf("%d-letter words only, or one of %s\n", u.wordLength, commands)
+	}
+}
+
+// command carries out one of the commands other than /giveup.
+func (u *UserBot) command(command string) {
+	if u.game == nil {
+		fmt.Printf("Commands are not available\n")
+		return
+	}
+	switch command {
+	case "/hint":
+		u.hint()
+	case "/history":
+		u.reported = 0
+		boards := gameBoards(u.game)
+		if len(boards) == 1 {
+			u.report(boards[0].Turns)
+			if u.color && len(boards[0].Turns) > 0 {
+				fmt.Printf("\n%s\n", keyboard(boards[0].Turns))
+			}
+		} else {
+			u.reportBoards(boards)
+		}
+	case "/undo":
+		if !*practice {
+			fmt.Printf("Guesses can only be taken back in practice mode (-practice)\n")
+			return
+		}
+		if err := u.game.Undo(); err != nil {
+			fmt.Printf("Cannot undo: %s\n", err)
+			return
+		}
+		u.reported--
+		fmt.Printf("Took back guess %d\n", u.reported+1)
+	default:
+		fmt.Printf("Unknown command %s\n", command)
+	}
+}
+
+// hint gives the first hint of the game as the number of words still
+// possible on each unsolved board, and each later hint as a letter of
+// the goal word of the first unsolved board that has been neither found
+// in place nor revealed by an earlier hint. In lying mode a letter shown
+// in place may be a lie, so only the hints count as revealing letters.
+// In adversarial mode no letters are given while the adversary has more
+// than one word left to choose from.
+func (u *UserBot) hint() {
+	u.hints++
+	boards := gameBoards(u.game)
+	goals := u.game.Goals()
+	if u.hints == 1 {
+		for i, b := range boards {
+			if !b.Solved {
+				n := len(remainingCandidates(u.words, b.Turns))
+				if len(boards) == 1 {
+					fmt.Printf("%d words are still possible\n", n)
+				} else {
+					fmt.Printf("Board %d: %d words are still possible\n", i+1, n)
+				}
+			}
+		}
+		return
+	}
+	if u.revealed == nil {
+		u.revealed = make([][]bool, len(boards))
+		for i := range u.revealed {
+			u.revealed[i] = make([]bool, u.wordLength)
+		}
+	}
+	for i, b := range boards {
+		if b.Solved {
+			continue
+		}
+		// In adversarial mode the goal word isn't chosen until only
+		// one candidate is left, so a letter of it could be false.
+		if n := len(u.game.Boards()[i].Candidates()); n > 1 {
+			fmt.Printf("%d words are still possible; no letters until the word is chosen\n", n)
+			return
+		}
+		for k := 0; k < u.wordLength; k++ {
+			found := u.revealed[i][k]
+			for _, t := range b.Turns {
+				if t.Signature[k] == gtw.LETTER_CORRECT && !*lying {
+					found = true
+				}
+			}
+			if !found {
+				u.revealed[i][k] = true
+				if len(boards) > 1 {
+					fmt.Printf("Board %d: ", i+1)
+				}
+				fmt.Printf("letter %d is %c\n", k+1, goals[i][k])
+				return
+			}
+		}
+		break
+	}
+	fmt.Printf("No more hints\n")
+}
+
+func (u *UserBot) EndGame(goal string, turns []Turn) {
+	u.report(turns)
+	won := len(turns) > 0 && turns[len(turns)-1].Guess == goal
+	if won {
+		fmt.Printf("Solved in %d guesses\n", len(turns))
+	} else {
+		fmt.Printf("The word was %s\n", goal)
+	}
+	if *share != "" {
+		u.share(goal, turns, won)
+	}
+	u.recordStats(won, len(turns))
+}
+
+// share prints the share grid for the game. The puzzle number is the
+// daily puzzle number in daily mode. Otherwise, as in the real game, it
+// is the position of the goal word in the answer list, counting from 0.
+// A goal word that isn't an answer is puzzle 0.
+func (u *UserBot) share(goal string, turns []Turn, won bool) {
+	signatures := make([]string, len(turns))
+	for i, t := range turns {
+		signatures[i] = t.Signature
+	}
+	puzzle := dailyPuzzle
+	if puzzle < 0 {
+		puzzle = findStringInSlice(goal, u.answers)
+	}
+	if puzzle < 0 {
+		puzzle = 0
+	}
+	fmt.Printf("\n%s\n\n", gtw.ShareGrid(puzzle, signatures, won, *turnLimit, *share == "ascii"))
+}
+
+// report prints the turns not yet shown to the user.
+func (u *UserBot) report(turns []Turn) {
+	for ; u.reported < len(turns); u.reported++ {
+		t := turns[u.reported]
+		if u.color {
+			fmt.Printf("       %s\n", tiles(t.Guess, t.Signature))
+		} else {
+			fmt.Printf("       %s (%d letters in the correct place)\n", gtw.Humanize(t.Signature, t.Guess), t.NCorrect)
+		}
+	}
+}
+
+// GuessBoards shows the user each new guess with its result on every
+// board, side by side, and reads the next guess. A board that is solved
+// is left blank.
+func (u *UserBot) GuessBoards(boards []Board) string {
+	u.reportBoards(boards)
+	return u.read()
+}
+
+func (u *UserBot) EndBoards(goals []string, boards []Board) {
+	u.reportBoards(boards)
+	solved := 0
+	for _, b := range boards {
+		if b.Solved {
+			solved++
+		}
+	}
+	if solved == len(boards) {
+		fmt.Printf("Solved all %d boards in %d guesses\n", len(boards), u.reported)
+	} else {
+		fmt.Printf("Solved %d of %d boards. The words were %s\n", solved, len(boards), strings.Join(goals, ", "))
+	}
+	u.recordStats(solved == len(boards), u.reported)
+}
+
+// recordStats adds the game to the player's statistics for the corpus
+// and mode, saves them and prints them. The file is read again for each
+// game in case another run has changed it. Problems with the file are
+// reported but don't stop the game. Practice games, in which guesses can
+// be taken back, and games abandoned at the end of the input don't count.
+func (u *UserBot) recordStats(won bool, guesses int) {
+	if *practice || u.abandoned {
+		return
+	}
+	path := *statsPath
+	if path == "" {
+		var err error
+		if path, err = gtw.DefaultStatsPath(); err != nil {
+			fmt.Printf("Cannot keep statistics: %s\n", err)
+			return
+		}
+	}
+	store, err := gtw.LoadStats(path)
+	if err != nil {
+		fmt.Printf("Cannot load statistics: %s\n", err)
+		return
+	}
+	stats := store.Get(gtw.StatsKey(gtw.CorpusID(u.answers), modeName()))
+	stats.Record(won, guesses)
+	if err := store.Save(); err != nil {
+		fmt.Printf("Cannot save statistics: %s\n", err)
+	}
+
+	fmt.Printf("Played %d  Win %% %.0f  Current streak %d  Max streak %d\n",
+		stats.Played, stats.WinPercent(), stats.CurrentStreak, stats.LongestStreak)
+	most := 0
+	for _, n := range stats.Distribution {
+		if n > most {
+			most = n
+		}
+	}
+	for i, n := range stats.Distribution {
+		bar := 0
+		if most > 0 {
+			bar = (n*20 + most - 1) / most
+		}
+		fmt.Printf("%3d | %s %d\n", i+1, strings.Repeat("#", bar), n)
+	}
+}
+
+// reportBoards prints the turns not yet shown to the user, one line per
+// turn with a column for each board.
+func (u *UserBot) reportBoards(boards []Board) {
+	turns := 0
+	for _, b := range boards {
+		if len(b.Turns) > turns {
+			turns = len(b.Turns)
+		}
+	}
+	for ; u.reported < turns; u.reported++ {
+		columns := make([]string, len(boards))
+		for i, b := range boards {
+			if u.reported < len(b.Turns) {
+				t := b.Turns[u.reported]
+				if u.color {
+					columns[i] = tiles(t.Guess, t.Signature)
+				} else {
+					columns[i] = gtw.Humanize(t.Signature, t.Guess)
+				}
+			} else if u.color {
+				columns[i] = strings.Repeat(" ", 3*u.wordLength)
+			} else {
+				columns[i] = strings.Repeat(" ", u.wordLength)
+			}
+		}
+		fmt.Printf("       %s\n", strings.Join(columns, "  "))
+	}
+}
+
+// colorTerminal returns true if the output is a terminal and the user
+// hasn't asked for no color by setting NO_COLOR (see no-color.org).
+func colorTerminal() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ANSI escape sequences for the tiles: black letters on green, black
+// on yellow and white on gray, and letters not yet guessed as they are.
+var tileColors = map[byte]string{
+	gtw.LETTER_CORRECT: "\x1b[1;30;42m",
+	gtw.LETTER_IN_WORD: "\x1b[1;30;43m",
+	gtw.LETTER_WRONG:   "\x1b[1;37;100m",
+	gtw.LETTER_INVALID: "\x1b[1m",
+}
+
+const colorReset = "\x1b[0m"
+
+// tile returns a letter drawn as a colored tile for its state, which is
+// a signature character or gtw.LETTER_INVALID for a letter not guessed.
+func tile(letter byte, state byte) string {
+	return tileColors[state] + " " + strings.ToUpper(string(letter)) + " " + colorReset
+}
+
+// tiles returns a guess drawn as a row of tiles colored by its signature.
+func tiles(guess string, signature string) string {
+	var result strings.Builder
+	for i := 0; i < len(guess); i++ {
+		result.WriteString(tile(guess[i], signature[i]))
+	}
+	return result.String()
+}
+
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// keyboard returns a QWERTY keyboard with each letter colored by the
+// best thing known about it from the turns: correct somewhere beats in
+// the word, which beats not in the word.
+func keyboard(turns []Turn) string {
+	rank := map[byte]int{gtw.LETTER_INVALID: 0, gtw.LETTER_WRONG: 1, gtw.LETTER_IN_WORD: 2, gtw.LETTER_CORRECT: 3}
+	state := make(map[byte]byte)
+	for _, t := range turns {
+		for i := 0; i < len(t.Guess); i++ {
+			if rank[t.Signature[i]] > rank[state[t.Guess[i]]] {
+				state[t.Guess[i]] = t.Signature[i]
+			}
+		}
+	}
+	var result strings.Builder
+	for r, row := range keyboardRows {
+		result.WriteString(strings.Repeat(" ", 7+2*r))
+		for i := 0; i < len(row); i++ {
+			result.WriteString(tile(row[i], state[row[i]]))
 		}
-		fmt.Println("5-letter words only")
+		result.WriteString("\n")
 	}
+	return result.String()
 }
@@ -0,0 +1,212 @@
+/*
+Command treegen searches for a decision tree that minimizes the expected
+number of guesses needed to find the words of a goal corpus, and writes
+the tree to a file that the cli's "tree" strategy can replay:
+
+	treegen -c wordle.corpus -a webster-2-all-five-letter.corpus -o wordle.tree -j 4
+	cli -c wordle.corpus -a webster-2-all-five-letter.corpus -s tree -- tree:wordle.tree
+
+At every node of the tree only the best -beam guesses, according to a
+cheap heuristic, are searched. With -beam 0 every guess is searched and
+the tree is optimal, but for a corpus the size of wordle.corpus that
+takes a very long time.
+
+The first guesses are searched in parallel by -j workers. The cost of
+each first guess is appended to a checkpoint file (by default the output
+file name followed by ".checkpoint") as soon as it is known. If treegen
+is interrupted, running it again with the same arguments skips the first
+guesses already in the checkpoint.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gmofishsauce/gtw/lib"
+)
+
+// Command line flags
+var corpusPath = flag.String("c", "", "required: `corpus-file` of goal words")
+var allowedPath = flag.String("a", "", "`guess-list` file of allowed guesses, default the corpus")
+var outputPath = flag.String("o", "", "required: `tree-file` to write")
+var checkpointPath = flag.String("checkpoint", "", "`checkpoint-file`, default the tree file name + .checkpoint")
+var beam = flag.Int("beam", 10, "number of guesses to search at each node, 0 for all (optimal, slow)")
+var jobs = flag.Int("j", 1, "search with `N` parallel workers")
+var verbose = flag.Bool("v", false, "enable verbose output")
+
+func main() {
+	flag.Parse()
+
+	if *corpusPath == "" || *outputPath == "" {
+		flag.PrintDefaults()
+		return
+	}
+	if *checkpointPath == "" {
+		*checkpointPath = *outputPath + ".checkpoint"
+	}
+
+	answers, err := gtw.LoadFile(*corpusPath)
+	if err != nil {
+		fmt.Printf("Cannot load corpus: %s\n", err)
+		return
+	}
+	wordLength, err := gtw.CorpusWordLength(answers)
+	if err != nil {
+		fmt.Printf("Bad corpus %s: %s\n", *corpusPath, err)
+		return
+	}
+	guesses := answers
+	if *allowedPath != "" {
+		allowed, err := gtw.LoadFile(*allowedPath)
+		if err != nil {
+			fmt.Printf("Cannot load allowed guesses: %s\n", err)
+			return
+		}
+		if n, err := gtw.CorpusWordLength(allowed); err != nil || n != wordLength {
+			fmt.Printf("Allowed guesses in %s must all have length %d\n", *allowedPath, wordLength)
+			return
+		}
+		guesses = gtw.MergeWordLists(allowed, answers)
+	}
+
+	search := &gtw.TreeSearch{Answers: answers, Guesses: guesses, Beam: *beam}
+	roots := search.RootGuesses()
+	header := fmt.Sprintf("# answers %s guesses %s beam %d", gtw.CorpusID(answers), gtw.CorpusID(guesses), *beam)
+	costs, err := readCheckpoint(*checkpointPath, header)
+	if err != nil {
+		fmt.Printf("Cannot use checkpoint: %s\n", err)
+		return
+	}
+	if *verbose {
+		fmt.Printf("%d answers, %d guesses, %d first guesses, %d already searched\n",
+			len(answers), len(guesses), len(roots), len(costs))
+	}
+
+	// Rewrite the checkpoint rather than appending to it, in case the
+	// last line was cut short when the previous run was interrupted.
+	checkpoint, err := os.Create(*checkpointPath)
+	if err != nil {
+		fmt.Printf("Cannot create checkpoint: %s\n", err)
+		return
+	}
+	fmt.Fprintln(checkpoint, header)
+	for _, g := range roots {
+		if cost, done := costs[g]; done {
+			fmt.Fprintf(checkpoint, "%s %d\n", g, cost)
+		}
+	}
+	searchRoots(answers, guesses, roots, costs, checkpoint)
+	checkpoint.Close()
+
+	best := ""
+	for _, g := range roots {
+		if best == "" || costs[g] < costs[best] {
+			best = g
+		}
+	}
+	if best == "" {
+		fmt.Printf("No useful first guess\n")
+		return
+	}
+
+	tree := search.Tree(best)
+	out, err := os.Create(*outputPath)
+	if err != nil {
+		fmt.Printf("Cannot create tree file: %s\n", err)
+		return
+	}
+	if err := gtw.WriteTree(out, tree); err != nil {
+		fmt.Printf("Cannot write tree file: %s\n", err)
+	}
+	out.Close()
+	fmt.Printf("TREE first guess %s : %4.4f guesses per goal\n", best, float64(tree.Cost())/float64(tree.Size()))
+}
+
+// searchRoots finds the cost of each first guess not already in costs,
+// using a pool of workers each having its own TreeSearch. Each new cost
+// is added to costs and written to the checkpoint.
+func searchRoots(answers []string, guesses []string, roots []string, costs map[string]int, checkpoint *os.File) {
+	type result struct {
+		guess string
+		cost  int
+	}
+	work := make(chan string)
+	results := make(chan result)
+	var wg sync.WaitGroup
+
+	workers := *jobs
+	if workers < 1 {
+		workers = 1
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			search := &gtw.TreeSearch{Answers: answers, Guesses: guesses, Beam: *beam}
+			for g := range work {
+				results <- result{g, search.Cost(g)}
+			}
+		}()
+	}
+	go func() {
+		for _, g := range roots {
+			if _, done := costs[g]; !done {
+				work <- g
+			}
+		}
+		close(work)
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		costs[r.guess] = r.cost
+		fmt.Fprintf(checkpoint, "%s %d\n", r.guess, r.cost)
+		if *verbose {
+			fmt.Printf("%s %d (%d of %d)\n", r.guess, r.cost, len(costs), len(roots))
+		}
+	}
+}
+
+// readCheckpoint returns the costs recorded in the checkpoint file. The
+// file must have been written for the same corpus, guesses and beam,
+// which are recorded in its header. A missing file is not an error. A
+// last line without a newline was cut short and is ignored.
+func readCheckpoint(path string, header string) (map[string]int, error) {
+	costs := make(map[string]int)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return costs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	lines = lines[:len(lines)-1] // complete lines only
+	for n, line := range lines {
+		if n == 0 {
+			if line != header {
+				return nil, fmt.Errorf("%s was written for different words or beam; remove it or use -checkpoint", path)
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		cost, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		costs[fields[0]] = cost
+	}
+	return costs, nil
+}
@@ -0,0 +1,184 @@
+package gtw
+
+import (
+	"math"
+)
+
+// PairedComparison is the comparison of two sets of paired measurements,
+// such as the number of guesses two bots needed for the same goal words.
+// The differences are a[i] - b[i].
+type PairedComparison struct {
+	N         int     // number of pairs
+	MeanDiff  float64 // mean difference
+	StdDev    float64 // sample standard deviation of the differences
+	CILow     float64 // the 95% confidence interval of the mean difference,
+	CIHigh    float64 // NaN if it cannot be estimated
+	Lower     int     // pairs in which a is lower than b
+	Ties      int     // pairs in which a equals b
+	Higher    int     // pairs in which a is higher than b
+	TTestP    float64 // two-sided p-value of the paired t-test, possibly NaN
+	SignTestP float64 // two-sided p-value of the sign test
+}
+
+// ComparePaired compares the paired measurements a and b, which must
+// have the same length. The confidence interval and the t-test use
+// Student's t distribution with N-1 degrees of freedom. They need at
+// least two pairs whose differences are not all the same; otherwise the
+// confidence interval is NaN, and so is the t-test's p-value unless every
+// difference is 0, when it is 1. The sign test, which only counts which
+// of a and b is lower in each pair, is exact.
+func ComparePaired(a []float64, b []float64) PairedComparison {
+	c := PairedComparison{N: len(a), CILow: math.NaN(), CIHigh: math.NaN()}
+	if c.N == 0 {
+		c.TTestP, c.SignTestP = 1, 1
+		return c
+	}
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d
+		switch {
+		case d < 0:
+			c.Lower++
+		case d > 0:
+			c.Higher++
+		default:
+			c.Ties++
+		}
+	}
+	c.MeanDiff = sum / float64(c.N)
+	if c.N > 1 {
+		squares := 0.0
+		for i := range a {
+			d := a[i] - b[i] - c.MeanDiff
+			squares += d * d
+		}
+		c.StdDev = math.Sqrt(squares / float64(c.N-1))
+	}
+	c.SignTestP = signTest(c.Lower, c.Higher)
+
+	if c.StdDev == 0 {
+		// The t statistic is undefined
+		c.TTestP = math.NaN()
+		if c.Ties == c.N {
+			c.TTestP = 1
+		}
+		return c
+	}
+	df := float64(c.N - 1)
+	stdErr := c.StdDev / math.Sqrt(float64(c.N))
+	margin := studentTCritical(0.05, df) * stdErr
+	c.CILow = c.MeanDiff - margin
+	c.CIHigh = c.MeanDiff + margin
+	c.TTestP = studentTP(c.MeanDiff/stdErr, df)
+	return c
+}
+
+// studentTP returns the two-sided p-value of t for Student's t
+// distribution with df degrees of freedom.
+func studentTP(t float64, df float64) float64 {
+	return incompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// studentTCritical returns the t for which the two-sided p-value is p,
+// found by bisection.
+func studentTCritical(p float64, df float64) float64 {
+	lo, hi := 0.0, 1.0
+	for studentTP(hi, df) > p {
+		lo, hi = hi, 2*hi
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTP(mid, df) > p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// incompleteBeta returns the regularized incomplete beta function
+// I_x(a, b), evaluated by its continued fraction (Numerical Recipes 6.4).
+func incompleteBeta(a float64, b float64, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly only below this point
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction for incompleteBeta by
+// the modified Lentz method.
+func betaFraction(a float64, b float64, x float64) float64 {
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		m2 := float64(2 * m)
+		fm := float64(m)
+		for _, aa := range []float64{
+			fm * (b - fm) * x / ((a + m2 - 1) * (a + m2)),
+			-(a + fm) * (a + b + fm) * x / ((a + m2) * (a + m2 + 1)),
+		} {
+			d = 1 + aa*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + aa/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
+
+// signTest returns the two-sided p-value of the sign test: the chance of
+// a split at least as uneven as lower to higher if each were equally
+// likely. Ties are left out.
+func signTest(lower int, higher int) float64 {
+	n := lower + higher
+	k := lower
+	if higher < k {
+		k = higher
+	}
+	// P(X <= k) for X binomial(n, 1/2), summed in log space because
+	// the binomial coefficients are enormous for thousands of pairs
+	p := 0.0
+	for i := 0; i <= k; i++ {
+		p += math.Exp(logChoose(n, i) - float64(n)*math.Ln2)
+	}
+	p *= 2
+	if p > 1 {
+		p = 1
+	}
+	return p
+}
+
+func logChoose(n int, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
@@ -0,0 +1,83 @@
+package gtw
+
+import (
+	"math"
+	"testing"
+)
+
+func near(x float64, y float64) bool {
+	return math.Abs(x-y) < 1e-6
+}
+
+func TestComparePaired(t *testing.T) {
+	a := []float64{3, 4, 3, 5, 4, 3}
+	b := []float64{4, 4, 5, 5, 5, 4}
+	c := ComparePaired(a, b)
+	// The differences are -1 0 -2 0 -1 -1
+	if c.N != 6 || c.Lower != 4 || c.Ties != 2 || c.Higher != 0 {
+		t.Error("wrong counts", c)
+	}
+	if !near(c.MeanDiff, -5.0/6.0) || !near(c.StdDev, math.Sqrt((1.0/36+25.0/36+49.0/36+25.0/36+1.0/36+1.0/36)/5)) {
+		t.Error("wrong mean or standard deviation", c.MeanDiff, c.StdDev)
+	}
+	if c.CILow >= c.MeanDiff || c.CIHigh <= c.MeanDiff || c.CIHigh >= 0 {
+		t.Error("wrong confidence interval", c.CILow, c.CIHigh)
+	}
+	if c.TTestP <= 0 || c.TTestP >= 0.05 {
+		t.Error("t-test should find a significant difference", c.TTestP)
+	}
+	// 4 lower and none higher: p = 2 * (1/2)^4
+	if !near(c.SignTestP, 0.125) {
+		t.Error("wrong sign test p-value", c.SignTestP)
+	}
+}
+
+func TestComparePairedSame(t *testing.T) {
+	a := []float64{3, 4, 5}
+	c := ComparePaired(a, a)
+	if c.MeanDiff != 0 || c.Ties != 3 || c.TTestP != 1 || c.SignTestP != 1 {
+		t.Error("identical measurements should not differ", c)
+	}
+}
+
+func TestSignTestLarge(t *testing.T) {
+	// Even splits are not significant, lopsided ones are, and large
+	// numbers of pairs don't overflow.
+	if p := signTest(1000, 1000); p < 0.9 || p > 1 {
+		t.Error("even split", p)
+	}
+	if p := signTest(900, 1100); p > 0.0001 || p <= 0 {
+		t.Error("uneven split", p)
+	}
+}
+
+func TestStudentT(t *testing.T) {
+	// Values from a table of the t distribution
+	if p := studentTP(2.015, 5); math.Abs(p-0.1) > 1e-3 {
+		t.Error("p-value for t=2.015 with 5 degrees of freedom", p)
+	}
+	if q := studentTCritical(0.05, 5); math.Abs(q-2.5706) > 1e-3 {
+		t.Error("critical t with 5 degrees of freedom", q)
+	}
+	if q := studentTCritical(0.05, 10000); math.Abs(q-1.96) > 1e-3 {
+		t.Error("critical t should approach the normal", q)
+	}
+}
+
+func TestComparePairedOnePair(t *testing.T) {
+	c := ComparePaired([]float64{5}, []float64{3})
+	if c.N != 1 || c.MeanDiff != 2 || !math.IsNaN(c.TTestP) || !math.IsNaN(c.CILow) || !math.IsNaN(c.CIHigh) {
+		t.Error("a single pair should have no t-test or confidence interval", c)
+	}
+}
+
+func TestComparePairedConstant(t *testing.T) {
+	c := ComparePaired([]float64{5, 6, 7}, []float64{3, 4, 5})
+	if c.MeanDiff != 2 || c.StdDev != 0 || !math.IsNaN(c.TTestP) || !math.IsNaN(c.CILow) || !math.IsNaN(c.CIHigh) {
+		t.Error("constant differences should have no t-test or confidence interval", c)
+	}
+	// The sign test still applies: 3 higher and none lower
+	if !near(c.SignTestP, 0.25) {
+		t.Error("wrong sign test p-value", c.SignTestP)
+	}
+}
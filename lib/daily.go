@@ -0,0 +1,44 @@
+package gtw
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// DailyEpoch is the date of daily puzzle number 0.
+var DailyEpoch = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// PuzzleNumber returns the number of the daily puzzle for a date, which
+// is the number of days from DailyEpoch to the calendar date in the
+// date's own location. So everyone sees a new puzzle at their midnight.
+// Dates before the epoch have negative numbers.
+func PuzzleNumber(date time.Time) int {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(DailyEpoch).Hours() / 24)
+}
+
+// PuzzleDate returns the date of a daily puzzle, the inverse of
+// PuzzleNumber.
+func PuzzleDate(puzzle int) time.Time {
+	return DailyEpoch.AddDate(0, 0, puzzle)
+}
+
+// DailyGoal returns the goal word of a daily puzzle. The goal words are
+// a shuffle of the corpus that depends only on the corpus (see CorpusID),
+// taken in order, so everyone playing the same puzzle with the same corpus
+// gets the same word and every word is used once before any is repeated.
+func DailyGoal(corpus []string, puzzle int) (string, error) {
+	if len(corpus) == 0 {
+		return "", fmt.Errorf("empty corpus")
+	}
+	if puzzle < 0 {
+		return "", fmt.Errorf("there is no daily puzzle before %s", DailyEpoch.Format("2006-01-02"))
+	}
+	h := fnv.New64a()
+	h.Write([]byte(CorpusID(corpus)))
+	order := rand.New(rand.NewSource(int64(h.Sum64()))).Perm(len(corpus))
+	return corpus[order[puzzle%len(corpus)]], nil
+}
@@ -0,0 +1,47 @@
+package gtw
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPuzzleNumber(t *testing.T) {
+	if n := PuzzleNumber(DailyEpoch); n != 0 {
+		t.Error("epoch should be puzzle 0", n)
+	}
+	// Late in the evening west of Greenwich it's still the same day
+	west := time.FixedZone("west", -8*60*60)
+	if n := PuzzleNumber(time.Date(2022, time.February, 1, 23, 30, 0, 0, west)); n != 31 {
+		t.Error("wrong puzzle number for 2022-02-01", n)
+	}
+	if n := PuzzleNumber(time.Date(2021, time.December, 31, 12, 0, 0, 0, time.UTC)); n != -1 {
+		t.Error("wrong puzzle number before the epoch", n)
+	}
+	if d := PuzzleDate(31).Format("2006-01-02"); d != "2022-02-01" {
+		t.Error("wrong date for puzzle 31", d)
+	}
+}
+
+func TestDailyGoal(t *testing.T) {
+	seen := make(map[string]bool)
+	for puzzle := 0; puzzle < len(treeAnswers); puzzle++ {
+		goal, err := DailyGoal(treeAnswers, puzzle)
+		if err != nil {
+			t.Fatal("DailyGoal", err)
+		}
+		if again, _ := DailyGoal(treeAnswers, puzzle); again != goal {
+			t.Error("DailyGoal is not reproducible", puzzle, goal, again)
+		}
+		if seen[goal] {
+			t.Error("goal repeated before the corpus was used up", puzzle, goal)
+		}
+		seen[goal] = true
+	}
+	first, _ := DailyGoal(treeAnswers, 0)
+	if wrapped, _ := DailyGoal(treeAnswers, len(treeAnswers)); wrapped != first {
+		t.Error("goals should repeat once the corpus is used up", first, wrapped)
+	}
+	if _, err := DailyGoal(treeAnswers, -1); err == nil {
+		t.Error("DailyGoal accepted a puzzle before the epoch")
+	}
+}
@@ -11,9 +11,11 @@ package gtw
 import (
 	"bufio"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"os"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 )
@@ -37,20 +39,92 @@ func LoadFile(filepath string) ([]string, error) {
 	return wordlist, nil
 }
 
+// MergeWordLists returns the words of the first list followed by
+// the words of the second list that are not in the first.
+func MergeWordLists(first []string, second []string) []string {
+	seen := make(map[string]bool, len(first))
+	result := make([]string, 0, len(first)+len(second))
+	for _, list := range [][]string{first, second} {
+		for _, w := range list {
+			if !seen[w] {
+				seen[w] = true
+				result = append(result, w)
+			}
+		}
+	}
+	return result
+}
+
+// CorpusID returns a short string identifying the contents of a
+// corpus. Corpora with the same words in the same order have the same
+// ID, so it can be used to check that saved results belong to a corpus.
+func CorpusID(corpus []string) string {
+	h := fnv.New64a()
+	for _, w := range corpus {
+		h.Write([]byte(w))
+		h.Write([]byte{'\n'})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// CorpusWordLength returns the length of the words in the corpus.
+// All the words in a corpus must have the same length; an error
+// is returned if they do not or if the corpus is empty.
+func CorpusWordLength(corpus []string) (int, error) {
+	if len(corpus) == 0 {
+		return 0, fmt.Errorf("empty corpus")
+	}
+	wordLength := len(corpus[0])
+	if wordLength == 0 {
+		return 0, fmt.Errorf("corpus word 0 is empty")
+	}
+	for i, w := range corpus {
+		if len(w) != wordLength {
+			return 0, fmt.Errorf("corpus word %d \"%s\" has length %d, expected %d", i, w, len(w), wordLength)
+		}
+	}
+	return wordLength, nil
+}
+
 // GtwEngine is a "game engine" for Guess the Word
+//
+// The engine holds the configuration shared by all games: the corpus,
+// the allowed guesses, hard mode and the turn limit. Each game is a
+// Game created by StartGame or StartRandomGame. Games may be played
+// concurrently provided the engine's configuration is not changed
+// while they are in progress. The methods NewGame, NewFixedGame,
+// Cheat and Score operate on a single "current" game owned by the
+// engine for callers that only need one game at a time.
 type GtwEngine struct {
-	corpus []string
-	rng    *rand.Rand
-	goal   string
+	corpus     []string
+	wordLength int
+	hardMode   bool
+	adversary  bool
+	lying      bool
+	allowed    map[string]bool // nil means any guess is allowed
+	allowedID  string          // CorpusID of the allowed guesses, "" if none
+	turnLimit  int             // 0 means no limit
+
+	rngLock sync.Mutex
+	rng     *rand.Rand
+	seed    int64
+
+	current *Game
 }
 
 // New creates a new GtW evaluation engine given a corpus of words.
-// The corpus may be constructed by LoadFile.
+// The corpus may be constructed by LoadFile. The word length of the
+// engine is taken from the corpus, so all the words in the corpus
+// must have the same length (see CorpusWordLength).
 func New(corpus []string) *GtwEngine {
 	if len(corpus) == 0 {
 		panic("0-length corpus ... ouch, don't do that")
 	}
-	result := &GtwEngine{corpus, nil, ""}
+	wordLength, err := CorpusWordLength(corpus)
+	if err != nil {
+		panic(err.Error())
+	}
+	result := &GtwEngine{corpus: corpus, wordLength: wordLength}
 	result.SetSeed(-1) // random
 	result.NewGame()
 	return result
@@ -61,30 +135,188 @@ func (e *GtwEngine) Corpus() []string {
 	return e.corpus
 }
 
-// Set the seed for the RNG
+// WordLength returns the length of the words in the engine's corpus.
+// Goal words and guesses must have this length.
+func (e *GtwEngine) WordLength() int {
+	return e.wordLength
+}
+
+// SetHardMode enables or disables hard mode. In hard mode every guess
+// must reuse all the information revealed by earlier guesses in the
+// same game: letters scored '+' must stay in place and letters scored
+// '*' must appear somewhere in the guess. Score rejects a guess that
+// violates these rules.
+func (e *GtwEngine) SetHardMode(hard bool) {
+	e.hardMode = hard
+}
+
+// HardMode returns true if the engine is in hard mode.
+func (e *GtwEngine) HardMode() bool {
+	return e.hardMode
+}
+
+// SetAdversarial enables or disables adversarial mode, in which the
+// goal word of a game is not fixed when the game starts (this mode is
+// known elsewhere as "Absurdle"). Instead, each game keeps the set of
+// corpus words that are still possible goals. When a guess is scored,
+// the engine groups these candidates by the signature the guess would
+// produce for each of them and commits to the signature that leaves the
+// largest group, which becomes the new candidate set. The game is won
+// when the guess is the only candidate left. Games started before the
+// call are not affected.
+func (e *GtwEngine) SetAdversarial(adversarial bool) {
+	e.adversary = adversarial
+}
+
+// Adversarial returns true if the engine is in adversarial mode.
+func (e *GtwEngine) Adversarial() bool {
+	return e.adversary
+}
+
+// SetLying enables or disables lying mode (known elsewhere as "Fibble").
+// In lying mode exactly one letter of every signature is wrong, except
+// that a winning guess is always scored truthfully. The wrong letter and
+// its false value are chosen by hashing the engine's seed (see SetSeed),
+// the goal word, the turn number and the guess, so a game played with the
+// same seed and guesses always tells the same lies, even if guesses were
+// taken back along the way. In adversarial mode, where the goal word is
+// chosen late, the lies depend only on the seed, turn and guess. Hard mode is not enforced in lying mode, because the player
+// cannot know which letters were really revealed. Games started before
+// the call are not affected. See RemainingWithLie.
+func (e *GtwEngine) SetLying(lying bool) {
+	e.lying = lying
+}
+
+// Lying returns true if the engine is in lying mode.
+func (e *GtwEngine) Lying() bool {
+	return e.lying
+}
+
+// SetAllowedGuesses installs a list of allowed guesses, separate from
+// the corpus of goal words. Once a list is installed, Score rejects any
+// guess that is neither in the list nor in the corpus. A nil or empty
+// list removes the restriction.
+func (e *GtwEngine) SetAllowedGuesses(words []string) {
+	if len(words) == 0 {
+		e.allowed = nil
+		e.allowedID = ""
+		return
+	}
+	e.allowedID = CorpusID(words)
+	e.allowed = make(map[string]bool, len(words)+len(e.corpus))
+	for _, w := range words {
+		e.allowed[w] = true
+	}
+	for _, w := range e.corpus {
+		e.allowed[w] = true
+	}
+}
+
+// IsAllowed returns true if the argument may be guessed: it must have
+// the engine's word length and, if an allowed-guess list is installed,
+// be in that list or in the corpus.
+func (e *GtwEngine) IsAllowed(guess string) bool {
+	if len(guess) != e.wordLength {
+		return false
+	}
+	return e.allowed == nil || e.allowed[guess]
+}
+
+// SetTurnLimit sets the number of scored guesses allowed in each game
+// started after the call. A game that reaches the limit without finding
+// the goal word is lost. A limit of 0 (the default) means no limit.
+func (e *GtwEngine) SetTurnLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	e.turnLimit = limit
+}
+
+// TurnLimit returns the turn limit for new games, 0 meaning no limit.
+func (e *GtwEngine) TurnLimit() int {
+	return e.turnLimit
+}
+
+// Set the seed for the RNG. A negative seed is replaced by one taken
+// from the time.
 func (e *GtwEngine) SetSeed(seed int64) {
 	if seed < 0 {
 		seed = time.Now().UnixNano()
 	}
+	e.rngLock.Lock()
 	e.rng = rand.New(rand.NewSource(seed))
+	e.seed = seed
+	e.rngLock.Unlock()
+}
+
+// Seed returns the seed last given to SetSeed, or the one chosen for it.
+func (e *GtwEngine) Seed() int64 {
+	e.rngLock.Lock()
+	defer e.rngLock.Unlock()
+	return e.seed
+}
+
+// StartGame creates a new game having the argument as its goal word.
+// The argument is not necessarily in the corpus, but it must have the
+// engine's word length. In adversarial mode the goal argument is
+// ignored beyond checking its length: every game starts with the whole
+// corpus as candidates, so games played the same way are the same game.
+func (e *GtwEngine) StartGame(goal string) (*Game, error) {
+	if len(goal) != e.wordLength {
+		return nil, fmt.Errorf("goal word \"%s\" has length %d, expected %d", goal, len(goal), e.wordLength)
+	}
+	game := &Game{engine: e, goal: goal, turnLimit: e.turnLimit}
+	if e.lying {
+		game.lying = true
+		game.lieSeed = e.Seed()
+		game.lieKey = goal
+	}
+	if e.adversary {
+		game.candidates = e.corpus
+		game.goal = e.corpus[0]
+		game.lieKey = ""
+	}
+	return game, nil
+}
+
+// StartRandomGame creates a new game having a uniformly-selected
+// random word from the engine's corpus as its goal word. It is safe
+// to call from multiple goroutines.
+func (e *GtwEngine) StartRandomGame() *Game {
+	e.rngLock.Lock()
+	goal := e.corpus[e.rng.Int31n(int32(len(e.corpus)))]
+	e.rngLock.Unlock()
+	game, _ := e.StartGame(goal)
+	return game
 }
 
 // NewGame reinitializes the goal word of the engine to a uniformly-
 // selected random word from the engine's corpus.
 func (e *GtwEngine) NewGame() {
-	e.goal = e.corpus[e.rng.Int31n(int32(len(e.corpus)))]
+	e.current = e.StartRandomGame()
 }
 
 // NewFixedGame reinitializes the goal word to the argument
-// The argument is not necessarily in the corpus.
+// The argument is not necessarily in the corpus, but it must
+// have the engine's word length.
 func (e *GtwEngine) NewFixedGame(aWord string) error {
-	e.goal = aWord
+	game, err := e.StartGame(aWord)
+	if err != nil {
+		return err
+	}
+	e.current = game
 	return nil
 }
 
 // Cheat returns the the engine's current goal word.
 func (e *GtwEngine) Cheat() string {
-	return e.goal
+	return e.current.Cheat()
+}
+
+// Game returns the engine's current game, the one started by the most
+// recent call to NewGame or NewFixedGame.
+func (e *GtwEngine) Game() *Game {
+	return e.current
 }
 
 const LETTER_CORRECT = '+' // This letter is correct and in position
@@ -92,26 +324,28 @@ const LETTER_IN_WORD = '*' // This letter is in the word, but out of position
 const LETTER_WRONG = '#'   // This letter is not in the word at any position
 const LETTER_INVALID = 0   // This can't ever occur in a guess or a goal
 
-// Score returns two values indicating the goodness of a guess.
-// The first return value is a string describing the match result
-// for the guess. In this string, '+' means the letter is in the
-// correct position in the goal word, '*' indicates the letter is
-// in the goal word but not in the correct position, and '#' means
-// the letter is not in the word. The integer value is the number
-// of '+' characters in the match result string. Note: the function
-// Humanize(signature, guess) can be used to produce a result string
-// is easier for humans to read from the result of this method.
-
-func (e *GtwEngine) Score(guess string) (string, int) {
-	var aGuess, aGoal, signature [5]rune
+// Score scores a guess against the engine's current game. See
+// Game.Score for the meaning of the results.
+func (e *GtwEngine) Score(guess string) (string, int, error) {
+	return e.current.Score(guess)
+}
 
-	if len(guess) != 5 {
-		return "#####", 0
+// Signature computes the signature of a guess against a goal word
+// of the same length and the number of correctly-placed letters,
+// exactly as Game.Score does but without any validation or history.
+// See Game.Score for the meaning of the signature. Bots can use it
+// to work out how the engine would score a guess for a candidate
+// goal word. It is called very often by some bots, so it avoids
+// allocating anything but the result.
+func Signature(guess string, goal string) (string, int) {
+	var buf [16]byte
+	var signature []byte
+	if len(goal) <= len(buf) {
+		signature = buf[:len(goal)]
+	} else {
+		signature = make([]byte, len(goal))
 	}
-
-	for i, _ := range(guess) {
-		aGuess[i] = rune(guess[i])
-		aGoal[i] = rune(e.goal[i])
+	for i := range signature {
 		signature[i] = LETTER_WRONG
 	}
 
@@ -121,37 +355,75 @@ func (e *GtwEngine) Score(guess string) (string, int) {
 	// and score any letter that still exists in the goal as
 	// an out-of-place letter.
 
-	unsolvedLetterCounts := make(map[rune]int)
+	var unsolvedLetterCounts [256]int
 
 	nCorrect := 0
-	for i, g := range(aGuess) {
-		if g == aGoal[i] {
-			aGuess[i] = 0
-			aGoal[i] = 0
+	for i := range signature {
+		if guess[i] == goal[i] {
 			signature[i] = LETTER_CORRECT
 			nCorrect++
 		} else {
-			count, exists := unsolvedLetterCounts[aGoal[i]]
+			unsolvedLetterCounts[goal[i]]++
+		}
+	}
 
-			if !exists {
-				count = 0
+	for i := range signature {
+		if signature[i] != LETTER_CORRECT {
+			g := guess[i]
+			if unsolvedLetterCounts[g] > 0 {
+				unsolvedLetterCounts[g]--
+				signature[i] = LETTER_IN_WORD
 			}
+		}
+	}
+
+	return string(signature), nCorrect
+}
 
-			unsolvedLetterCounts[aGoal[i]] = count + 1
+// Remaining returns the words that could still be the goal word
+// after the guess was scored with the signature, i.e. the words
+// for which Signature(guess, word) would return the signature.
+func Remaining(words []string, guess string, signature string) []string {
+	result := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) != len(guess) {
+			continue
+		}
+		if s, _ := Signature(guess, w); s == signature {
+			result = append(result, w)
 		}
 	}
+	return result
+}
 
-	for i, g := range(aGuess) {
-		if aGuess[i] != 0 {
-			count, exists := unsolvedLetterCounts[g]
-			if exists && count > 0 {
-				unsolvedLetterCounts[g] = count - 1
-				signature[i] = LETTER_IN_WORD
+// RemainingWithLie is Remaining for lying mode (see SetLying). It
+// returns the words for which Signature(guess, word) differs from the
+// signature in exactly one letter. A winning guess is never lied about,
+// so the guess itself is not returned unless the signature is a win.
+func RemainingWithLie(words []string, guess string, signature string) []string {
+	if strings.Count(signature, string(LETTER_CORRECT)) == len(signature) {
+		return Remaining(words, guess, signature)
+	}
+	result := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) != len(guess) {
+			continue
+		}
+		s, nCorrect := Signature(guess, w)
+		if nCorrect == len(s) {
+			continue
+		}
+		differences := 0
+		for i := 0; i < len(s); i++ {
+			if s[i] != signature[i] {
+				differences++
 			}
 		}
+		if differences == 1 {
+			result = append(result, w)
+		}
 	}
-
-	return string(signature[:]), nCorrect
+	return result
 }
 
 // Humanize the result of a guess. Given a signature like "++##*"
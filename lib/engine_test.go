@@ -83,7 +83,7 @@ func TestBugScore(t *testing.T) {
 	}
 	engine := New(corpus)
 	expected := "++#+#"
-	signature, _ := engine.Score("tater")
+	signature, _, _ := engine.Score("tater")
 	if signature != expected {
 		t.Error("got ", signature, "expected", expected)
 	}
@@ -103,7 +103,7 @@ func TestBug3Score(t *testing.T) {
 	}
 	engine := New(corpus)
 	expected := "#+#+#"
-	signature, _ := engine.Score("brush")
+	signature, _, _ := engine.Score("brush")
 	if signature != expected {
 		t.Error("got ", signature, "expected", expected)
 	}
@@ -111,11 +111,11 @@ func TestBug3Score(t *testing.T) {
 
 func TestScore(t *testing.T) {
 	engine := New(loadTestCorpus(t))
-	signature, score := engine.Score("xyzzy")
+	signature, score, _ := engine.Score("xyzzy")
 	if signature != "#####" || score != 0 {
 		t.Error("wrong score for xyzzy", signature, score)
 	}
-	signature, score = engine.Score(engine.Cheat())
+	signature, score, _ = engine.Score(engine.Cheat())
 	if signature != "+++++" || score != 5 {
 		t.Error("wrong score for blind", signature, score)
 	}
@@ -129,7 +129,7 @@ func TestScore(t *testing.T) {
 		}
 		reversed.WriteRune(r)
 	}
-	signature, score = engine.Score(reversed.String())
+	signature, score, _ = engine.Score(reversed.String())
 	if signature != "**#**" || score != 0 {
 		t.Error("wrong score for reversed goal", signature, score)
 	}
@@ -138,7 +138,7 @@ func TestScore(t *testing.T) {
 func TestScoreOnlyFirstTwoTeesInWrongPlace(t *testing.T) {
 	engine := New([]string{"twist"})
 	engine.NewFixedGame("twist")
-	signature, score := engine.Score("ottto")
+	signature, score, _ := engine.Score("ottto")
 	if signature != "#**##" || score != 0 {
 		t.Error("wrong signature or score for ottto", signature, score, engine.Cheat())
 	}
@@ -148,7 +148,7 @@ func TestFixedGame(t *testing.T) {
 	engine := New(loadTestCorpus(t))
 	aWord := engine.Corpus()[0]
 	engine.NewFixedGame(aWord)
-	signature, score := engine.Score(aWord)
+	signature, score, _ := engine.Score(aWord)
 	if signature != "+++++" || score != 5 {
 		t.Error("wrong score for specified goal word", signature, score, aWord)
 	}
@@ -160,3 +160,184 @@ func TestHumanize(t *testing.T) {
 		t.Error("Humanize(++##*, after): bad result", result)
 	}
 }
+
+func TestWordLength(t *testing.T) {
+	engine := New([]string{"tree", "leaf"})
+	if engine.WordLength() != 4 {
+		t.Error("wrong word length for 4-letter corpus", engine.WordLength())
+	}
+	engine.NewFixedGame("tree")
+	signature, score, _ := engine.Score("reed")
+	if signature != "**+#" || score != 1 {
+		t.Error("wrong signature or score for reed", signature, score)
+	}
+	if _, _, err := engine.Score("trees"); err == nil {
+		t.Error("wrong-length guess was not rejected")
+	}
+
+	engine = New([]string{"planets"})
+	signature, score, _ = engine.Score("planets")
+	if signature != "+++++++" || score != 7 {
+		t.Error("wrong signature or score for 7-letter goal", signature, score)
+	}
+}
+
+func TestCorpusWordLength(t *testing.T) {
+	if n, err := CorpusWordLength(loadedTestData); err != nil || n != 5 {
+		t.Error("CorpusWordLength: test data", n, err)
+	}
+	if _, err := CorpusWordLength([]string{"three", "four"}); err == nil {
+		t.Error("CorpusWordLength: mixed lengths accepted")
+	}
+	if _, err := CorpusWordLength(nil); err == nil {
+		t.Error("CorpusWordLength: empty corpus accepted")
+	}
+}
+
+func TestMergeWordLists(t *testing.T) {
+	merged := MergeWordLists([]string{"blind", "three"}, loadedTestData)
+	expected := []string{"blind", "three", "mices"}
+	if len(merged) != len(expected) {
+		t.Fatal("MergeWordLists: wrong length", merged)
+	}
+	for i, w := range expected {
+		if merged[i] != w {
+			t.Error("MergeWordLists: got", merged, "expected", expected)
+		}
+	}
+}
+
+func TestCorpusID(t *testing.T) {
+	id := CorpusID(loadedTestData)
+	if id != CorpusID([]string{"three", "blind", "mices"}) {
+		t.Error("CorpusID: same corpus, different IDs")
+	}
+	if id == CorpusID([]string{"blind", "three", "mices"}) || id == CorpusID(loadedTestData[:2]) {
+		t.Error("CorpusID: different corpora, same ID")
+	}
+}
+
+func TestFixedGameWrongLength(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	if err := engine.NewFixedGame("four"); err == nil {
+		t.Error("NewFixedGame accepted a goal of the wrong length")
+	}
+}
+
+func TestHardMode(t *testing.T) {
+	engine := New([]string{"cross"})
+	engine.SetHardMode(true)
+	engine.NewFixedGame("cross")
+	if _, _, err := engine.Score("brush"); err != nil {
+		t.Error("hard mode rejected the first guess", err)
+	}
+	if _, _, err := engine.Score("tramp"); err == nil {
+		t.Error("hard mode accepted a guess that moved a correct letter")
+	}
+	if _, _, err := engine.Score("dries"); err == nil {
+		t.Error("hard mode accepted a guess that dropped a correct letter")
+	}
+	if _, _, err := engine.Score("arose"); err != nil {
+		t.Error("hard mode rejected a guess that reused all the information", err)
+	}
+	signature, score, err := engine.Score("cross")
+	if err != nil || signature != "+++++" || score != 5 {
+		t.Error("hard mode: wrong result for the goal word", signature, score, err)
+	}
+	if engine.Game().Turns() != 3 {
+		t.Error("hard mode: rejected guesses should not be recorded", engine.Game().Guesses())
+	}
+}
+
+func TestHardModeInWord(t *testing.T) {
+	engine := New([]string{"taken"})
+	engine.SetHardMode(true)
+	engine.NewFixedGame("taken")
+	signature, _, _ := engine.Score("eaten")
+	if signature != "#+*++" {
+		t.Fatal("unexpected signature for eaten", signature)
+	}
+	if _, _, err := engine.Score("wagon"); err == nil {
+		t.Error("hard mode accepted a guess missing out-of-place letters")
+	}
+	if _, _, err := engine.Score("taken"); err != nil {
+		t.Error("hard mode rejected the goal word", err)
+	}
+
+	engine.SetHardMode(false)
+	engine.NewFixedGame("taken")
+	engine.Score("eaten")
+	if _, _, err := engine.Score("wagon"); err != nil {
+		t.Error("normal mode rejected a guess", err)
+	}
+}
+
+func TestAllowedGuesses(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.NewFixedGame("blind")
+	if _, _, err := engine.Score("xvqzw"); err != nil {
+		t.Error("guess rejected with no allowed-guess list", err)
+	}
+	engine.SetAllowedGuesses([]string{"bland", "blond"})
+	if _, _, err := engine.Score("xvqzw"); err == nil {
+		t.Error("guess not in the allowed-guess list was accepted")
+	}
+	if _, _, err := engine.Score("?????"); err == nil {
+		t.Error("guess not in the allowed-guess list was accepted")
+	}
+	signature, _, err := engine.Score("bland")
+	if err != nil || signature != "++#++" {
+		t.Error("allowed guess: wrong result", signature, err)
+	}
+	if _, _, err := engine.Score("mices"); err != nil {
+		t.Error("corpus word rejected as a guess", err)
+	}
+	if engine.Game().Turns() != 3 {
+		t.Error("rejected guesses should not be recorded", engine.Game().Guesses())
+	}
+	engine.SetAllowedGuesses(nil)
+	if !engine.IsAllowed("xvqzw") || engine.IsAllowed("four") {
+		t.Error("IsAllowed: wrong result after removing the allowed-guess list")
+	}
+}
+
+func TestSignature(t *testing.T) {
+	cases := []struct{ guess, goal, signature string }{
+		{"tater", "taken", "++#+#"},
+		{"brush", "cross", "#+#+#"},
+		{"ottto", "twist", "#**##"},
+		{"after", "after", "+++++"},
+		{"eerie", "there", "*#*#+"},
+	}
+	for _, c := range cases {
+		signature, nCorrect := Signature(c.guess, c.goal)
+		if signature != c.signature || nCorrect != strings.Count(c.signature, "+") {
+			t.Errorf("Signature(%s, %s): got %s %d, expected %s", c.guess, c.goal, signature, nCorrect, c.signature)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	words := []string{"cross", "crush", "brush", "gloss", "tramp"}
+	remaining := Remaining(words, "brush", "#+#+#")
+	if len(remaining) != 1 || remaining[0] != "cross" {
+		t.Error("Remaining: wrong result", remaining)
+	}
+	remaining = Remaining(words, "tramp", "#####")
+	if len(remaining) != 1 || remaining[0] != "gloss" {
+		t.Error("Remaining: wrong result", remaining)
+	}
+}
+
+func TestRemainingWithLie(t *testing.T) {
+	words := []string{"cross", "crush", "brush", "gloss", "tramp"}
+	// The truth for cross is "#+#+#", for crush "#++++" and for gloss "###+#"
+	remaining := RemainingWithLie(words, "brush", "#+++#")
+	if len(remaining) != 2 || remaining[0] != "cross" || remaining[1] != "crush" {
+		t.Error("RemainingWithLie: wrong result", remaining)
+	}
+	remaining = RemainingWithLie(words, "brush", "++++#")
+	if len(remaining) != 0 {
+		t.Error("RemainingWithLie: the guess itself is only a candidate if it won", remaining)
+	}
+}
@@ -0,0 +1,253 @@
+package gtw
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Game is a single game of Guess the Word. It holds its own goal word,
+// the history of scored guesses and their signatures, and the turn
+// limit. Games are created by the engine's StartGame and
+// StartRandomGame methods and share the engine's read-only corpus and
+// configuration, so many games can be played at once. A single Game is
+// not safe for concurrent use.
+//
+// In adversarial mode (see SetAdversarial) a game has a set of candidate
+// goal words rather than a single goal word.
+type Game struct {
+	engine     *GtwEngine
+	goal       string
+	candidates []string // nil unless adversarial
+	guesses    []string
+	signatures []string
+	turnLimit  int // 0 means no limit
+	won        bool
+	lying      bool
+	lieSeed    int64  // the engine's seed when the game started
+	lieKey     string // the goal word the lies depend on (see SetLying)
+}
+
+// Score returns three values indicating the goodness of a guess.
+// The first return value is a string describing the match result
+// for the guess. In this string, '+' means the letter is in the
+// correct position in the goal word, '*' indicates the letter is
+// in the goal word but not in the correct position, and '#' means
+// the letter is not in the word. The integer value is the number
+// of '+' characters in the match result string. Note: the function
+// Humanize(signature, guess) can be used to produce a result string
+// is easier for humans to read from the result of this method.
+// The error is non-nil if the guess was rejected rather than scored:
+// the game is over, the guess has the wrong length, is not an allowed
+// guess (see SetAllowedGuesses), or breaks the hard mode rules. A
+// rejected guess does not become part of the game's history.
+func (g *Game) Score(guess string) (string, int, error) {
+	if err := g.check(guess); err != nil {
+		return "", 0, err
+	}
+	signature, nCorrect := g.score(guess)
+	return signature, nCorrect, nil
+}
+
+// check returns the reason the guess would be rejected, or nil.
+func (g *Game) check(guess string) error {
+	if g.Over() {
+		return fmt.Errorf("the game is over")
+	}
+	e := g.engine
+	if len(guess) != e.wordLength {
+		return fmt.Errorf("\"%s\" has length %d, expected %d", guess, len(guess), e.wordLength)
+	}
+	if !e.IsAllowed(guess) {
+		return fmt.Errorf("\"%s\" is not in the word list", guess)
+	}
+	if e.hardMode && !g.lying {
+		return CheckHardMode(guess, g.guesses, g.signatures)
+	}
+	return nil
+}
+
+// score scores a guess that has passed check and adds it to the history.
+func (g *Game) score(guess string) (string, int) {
+	var signature string
+	var nCorrect int
+	if g.candidates != nil {
+		signature, nCorrect = g.adversarialSignature(guess)
+	} else {
+		signature, nCorrect = Signature(guess, g.goal)
+	}
+	if nCorrect == len(g.goal) {
+		g.won = true
+	} else if g.lying {
+		signature, nCorrect = g.lie(guess, signature)
+	}
+	g.guesses = append(g.guesses, guess)
+	g.signatures = append(g.signatures, signature)
+	return signature, nCorrect
+}
+
+// lie changes one letter of a signature that isn't a win to one of the
+// other two values, never making it look like a win. The choices come
+// from a hash of the seed, the goal word, the turn and the guess, so the
+// lie can be told again from the game log (see SetLying).
+func (g *Game) lie(guess string, signature string) (string, int) {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d %s %d %s", g.lieSeed, g.lieKey, len(g.guesses), guess)
+	x := h.Sum64()
+	values := []byte{LETTER_CORRECT, LETTER_IN_WORD, LETTER_WRONG}
+	lie := []byte(signature)
+	i := int(x % uint64(len(lie)))
+	x /= uint64(len(lie))
+	var choices []byte
+	for _, v := range values {
+		if v != lie[i] {
+			choices = append(choices, v)
+		}
+	}
+	lie[i] = choices[x%uint64(len(choices))]
+	if strings.Count(string(lie), string(LETTER_CORRECT)) == len(lie) {
+		lie[i] = LETTER_IN_WORD
+	}
+	result := string(lie)
+	return result, strings.Count(result, string(LETTER_CORRECT))
+}
+
+// adversarialSignature chooses the signature for the guess that leaves
+// the most candidates, and keeps only those candidates. Ties go to the
+// signature with the fewest correct letters, then the fewest letters in
+// the word, then the first in sort order, so the choice is reproducible.
+func (g *Game) adversarialSignature(guess string) (string, int) {
+	buckets := make(map[string][]string)
+	for _, c := range g.candidates {
+		signature, _ := Signature(guess, c)
+		buckets[signature] = append(buckets[signature], c)
+	}
+	best := ""
+	for signature, bucket := range buckets {
+		if best == "" || adversaryPrefers(signature, len(bucket), best, len(buckets[best])) {
+			best = signature
+		}
+	}
+	g.candidates = buckets[best]
+	g.goal = g.candidates[0]
+	return best, strings.Count(best, string(LETTER_CORRECT))
+}
+
+func adversaryPrefers(s1 string, n1 int, s2 string, n2 int) bool {
+	if n1 != n2 {
+		return n1 > n2
+	}
+	correct1 := strings.Count(s1, string(LETTER_CORRECT))
+	correct2 := strings.Count(s2, string(LETTER_CORRECT))
+	if correct1 != correct2 {
+		return correct1 < correct2
+	}
+	inWord1 := strings.Count(s1, string(LETTER_IN_WORD))
+	inWord2 := strings.Count(s2, string(LETTER_IN_WORD))
+	if inWord1 != inWord2 {
+		return inWord1 < inWord2
+	}
+	return s1 < s2
+}
+
+// CheckHardMode returns an error describing the first way in which the
+// guess fails to reuse information revealed by the earlier guesses and
+// their signatures, or nil if the guess would be allowed in hard mode.
+func CheckHardMode(guess string, guesses []string, signatures []string) error {
+	for k, previous := range guesses {
+		signature := signatures[k]
+		required := make(map[byte]int)
+		for i := 0; i < len(signature); i++ {
+			switch signature[i] {
+			case LETTER_CORRECT:
+				if guess[i] != previous[i] {
+					return fmt.Errorf("hard mode: letter %d of \"%s\" must be %c", i+1, guess, previous[i])
+				}
+				required[previous[i]]++
+			case LETTER_IN_WORD:
+				required[previous[i]]++
+			}
+		}
+		for i := 0; i < len(previous); i++ {
+			letter := previous[i]
+			if n := strings.Count(guess, string(letter)); n < required[letter] {
+				return fmt.Errorf("hard mode: \"%s\" must contain %c at least %d time(s)", guess, letter, required[letter])
+			}
+		}
+	}
+	return nil
+}
+
+// Undo takes back the last scored guess, as if it had never been made.
+// A game that the guess won or lost is in progress again. In adversarial
+// mode the candidates that the adversary dropped cannot be brought back,
+// so Undo returns an error. In lying mode the guess is told the same lie
+// if it is made again.
+func (g *Game) Undo() error {
+	if len(g.guesses) == 0 {
+		return fmt.Errorf("there is no guess to take back")
+	}
+	if g.candidates != nil {
+		return fmt.Errorf("guesses cannot be taken back in adversarial mode")
+	}
+	g.guesses = g.guesses[:len(g.guesses)-1]
+	g.signatures = g.signatures[:len(g.signatures)-1]
+	g.won = false
+	return nil
+}
+
+// Cheat returns the game's goal word. In adversarial mode it returns one
+// of the remaining candidates, which is the goal word once the game has
+// been won.
+func (g *Game) Cheat() string {
+	return g.goal
+}
+
+// Candidates returns the words that could still be the goal word. For
+// a game with a fixed goal word this is just the goal word.
+func (g *Game) Candidates() []string {
+	if g.candidates == nil {
+		return []string{g.goal}
+	}
+	return append([]string(nil), g.candidates...)
+}
+
+// Guesses returns a copy of the scored guesses in the order they
+// were made. Rejected guesses are not included.
+func (g *Game) Guesses() []string {
+	return append([]string(nil), g.guesses...)
+}
+
+// Signatures returns a copy of the signatures of the scored guesses,
+// in the same order as Guesses.
+func (g *Game) Signatures() []string {
+	return append([]string(nil), g.signatures...)
+}
+
+// Turns returns the number of scored guesses.
+func (g *Game) Turns() int {
+	return len(g.guesses)
+}
+
+// TurnLimit returns the number of scored guesses allowed in the game,
+// 0 meaning no limit.
+func (g *Game) TurnLimit() int {
+	return g.turnLimit
+}
+
+// Won returns true if the goal word has been guessed.
+func (g *Game) Won() bool {
+	return g.won
+}
+
+// Lost returns true if the turn limit was reached without guessing
+// the goal word.
+func (g *Game) Lost() bool {
+	return !g.won && g.turnLimit > 0 && len(g.guesses) >= g.turnLimit
+}
+
+// Over returns true if the game has been won or lost. No more guesses
+// are scored once the game is over.
+func (g *Game) Over() bool {
+	return g.won || g.Lost()
+}
@@ -0,0 +1,214 @@
+package gtw
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGameHistory(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	game, err := engine.StartGame("blind")
+	if err != nil {
+		t.Fatal("StartGame", err)
+	}
+	game.Score("three")
+	game.Score("mices")
+	guesses := game.Guesses()
+	signatures := game.Signatures()
+	if len(guesses) != 2 || guesses[0] != "three" || guesses[1] != "mices" {
+		t.Error("wrong guess history", guesses)
+	}
+	if len(signatures) != 2 || signatures[0] != "#####" || signatures[1] != "#*###" {
+		t.Error("wrong signature history", signatures)
+	}
+	if game.Turns() != 2 || game.Over() {
+		t.Error("game should be in progress after 2 turns", game.Turns())
+	}
+	signature, nCorrect, err := game.Score("blind")
+	if err != nil || signature != "+++++" || nCorrect != 5 {
+		t.Error("wrong result for goal word", signature, nCorrect, err)
+	}
+	if !game.Won() || game.Lost() || !game.Over() {
+		t.Error("game should be won")
+	}
+	if _, _, err := game.Score("blind"); err == nil {
+		t.Error("guess scored after the game was over")
+	}
+}
+
+func TestGameTurnLimit(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.SetTurnLimit(2)
+	game, _ := engine.StartGame("blind")
+	if game.TurnLimit() != 2 {
+		t.Error("wrong turn limit", game.TurnLimit())
+	}
+	game.Score("three")
+	if game.Over() {
+		t.Error("game over after 1 turn of 2")
+	}
+	game.Score("mices")
+	if !game.Lost() || game.Won() || !game.Over() {
+		t.Error("game should be lost after 2 turns of 2")
+	}
+	if _, _, err := game.Score("blind"); err == nil {
+		t.Error("guess scored after the game was lost")
+	}
+}
+
+func TestStartGameWrongLength(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	if _, err := engine.StartGame("four"); err == nil {
+		t.Error("StartGame accepted a goal of the wrong length")
+	}
+}
+
+func TestConcurrentGames(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.SetHardMode(true)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(goal string) {
+			defer wg.Done()
+			game, _ := engine.StartGame(goal)
+			for _, w := range loadedTestData {
+				if w == goal {
+					break
+				}
+				game.Score(w)
+			}
+			if _, _, err := game.Score(goal); err != nil || !game.Won() {
+				t.Error("concurrent game not won", goal, err)
+			}
+			engine.StartRandomGame()
+		}(loadedTestData[i%len(loadedTestData)])
+	}
+	wg.Wait()
+}
+
+func TestAdversarialGame(t *testing.T) {
+	engine := New(treeAnswers)
+	engine.SetAdversarial(true)
+	game, _ := engine.StartGame("cigar")
+	if len(game.Candidates()) != len(treeAnswers) {
+		t.Fatal("adversarial game should start with the whole corpus", len(game.Candidates()))
+	}
+	signature, _, _ := game.Score("cigar")
+	if signature == "+++++" {
+		t.Error("adversary gave up the goal word on the first guess")
+	}
+	for game.Turns() < 20 && !game.Won() {
+		before := len(game.Candidates())
+		candidates := game.Candidates()
+		guess := candidates[0]
+		signature, _, err := game.Score(guess)
+		if err != nil {
+			t.Fatal("Score", err)
+		}
+		for _, c := range game.Candidates() {
+			if s, _ := Signature(guess, c); s != signature {
+				t.Error("candidate inconsistent with the signature", c, s, signature)
+			}
+		}
+		if !game.Won() && len(game.Candidates()) >= before {
+			t.Error("guessing a candidate did not shrink the candidate set")
+		}
+	}
+	if !game.Won() || len(game.Candidates()) != 1 || game.Cheat() != game.Guesses()[game.Turns()-1] {
+		t.Error("adversarial game not won by guessing the last candidate", game.Candidates())
+	}
+}
+
+func TestAdversaryKeepsLargestBucket(t *testing.T) {
+	engine := New([]string{"cross", "gross", "floss", "brush"})
+	engine.SetAdversarial(true)
+	game, _ := engine.StartGame("cross")
+	signature, _, _ := game.Score("floss")
+	// cross and gross both score "##+++", floss "+++++", brush "###+#"
+	if signature != "##+++" || len(game.Candidates()) != 2 {
+		t.Error("adversary did not keep the largest bucket", signature, game.Candidates())
+	}
+}
+
+func TestLyingGame(t *testing.T) {
+	play := func() []string {
+		engine := New(treeAnswers)
+		engine.SetLying(true)
+		engine.SetSeed(42)
+		game, _ := engine.StartGame("cigar")
+		for _, guess := range []string{"rebut", "sissy", "humph", "cigar"} {
+			signature, nCorrect, err := game.Score(guess)
+			if err != nil {
+				t.Fatal("Score", err)
+			}
+			truth, _ := Signature(guess, "cigar")
+			if guess == "cigar" {
+				if signature != truth || !game.Won() {
+					t.Error("winning guess was lied about", signature)
+				}
+				continue
+			}
+			differences := 0
+			for i := range truth {
+				if truth[i] != signature[i] {
+					differences++
+				}
+			}
+			if differences != 1 || nCorrect != strings.Count(signature, "+") {
+				t.Error("signature should have exactly one lie", guess, truth, signature, nCorrect)
+			}
+			if len(RemainingWithLie(treeAnswers, guess, signature)) == 0 {
+				t.Error("RemainingWithLie lost the goal word", guess, signature)
+			}
+		}
+		return game.Signatures()
+	}
+	first := play()
+	second := play()
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Error("same seed told different lies", first, second)
+	}
+}
+
+func TestGameUndo(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.SetTurnLimit(2)
+	game, _ := engine.StartGame("blind")
+	game.Score("three")
+	game.Score("mices")
+	if !game.Lost() {
+		t.Fatal("game should be lost after 2 turns of 2")
+	}
+	if err := game.Undo(); err != nil || game.Over() || game.Turns() != 1 {
+		t.Error("Undo should put the game back in progress", err, game.Turns())
+	}
+	if signature, _, err := game.Score("blind"); err != nil || signature != "+++++" || !game.Won() {
+		t.Error("game not won after Undo", signature, err)
+	}
+	game.Undo()
+	game.Undo()
+	if err := game.Undo(); err == nil || game.Turns() != 0 {
+		t.Error("Undo with no guesses", game.Turns())
+	}
+
+	engine.SetAdversarial(true)
+	game, _ = engine.StartGame("blind")
+	game.Score("three")
+	if err := game.Undo(); err == nil {
+		t.Error("Undo in adversarial mode")
+	}
+}
+
+func TestCheckHardMode(t *testing.T) {
+	// "mices" against "blind": the i is in the word, the rest are not
+	guesses := []string{"mices"}
+	signatures := []string{"#*###"}
+	if err := CheckHardMode("blind", guesses, signatures); err != nil {
+		t.Error("blind should be allowed", err)
+	}
+	if err := CheckHardMode("three", guesses, signatures); err == nil {
+		t.Error("three should be rejected for leaving out the i")
+	}
+}
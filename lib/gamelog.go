@@ -0,0 +1,178 @@
+package gtw
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A game log records games so they can be replayed and compared word by
+// word, for instance before and after a change to a bot. It is a text
+// file of JSON objects, one per line: a LogHeader describing the engine,
+// then a LogEntry for each game.
+
+// LogHeader describes the engine that played the games of a log. The
+// corpus and allowed guesses are identified by their CorpusID.
+type LogHeader struct {
+	Answers     string `json:"answers"`
+	Guesses     string `json:"guesses"` // "" if any guess was allowed
+	Seed        int64  `json:"seed"`
+	HardMode    bool   `json:"hardMode"`
+	Adversarial bool   `json:"adversarial"`
+	Lying       bool   `json:"lying"`
+	TurnLimit   int    `json:"turnLimit"`
+}
+
+// LogEntry is the record of one game: the bot that played it, the goal
+// word of each board, the guesses, and for each guess the signature on
+// each board, "" for a board that was already solved.
+type LogEntry struct {
+	Bot        string     `json:"bot"`
+	Goals      []string   `json:"goals"`
+	Guesses    []string   `json:"guesses"`
+	Signatures [][]string `json:"signatures"`
+}
+
+// LogHeader returns the header for a log of the engine's games.
+func (e *GtwEngine) LogHeader() LogHeader {
+	return LogHeader{
+		Answers:     CorpusID(e.corpus),
+		Guesses:     e.allowedID,
+		Seed:        e.Seed(),
+		HardMode:    e.hardMode,
+		Adversarial: e.adversary,
+		Lying:       e.lying,
+		TurnLimit:   e.turnLimit,
+	}
+}
+
+// ApplyLogHeader configures the engine to play as the engine that wrote
+// the log did. An error is returned if the engine's corpus or allowed
+// guesses are not the ones recorded in the header.
+func (e *GtwEngine) ApplyLogHeader(h LogHeader) error {
+	if id := CorpusID(e.corpus); id != h.Answers {
+		return fmt.Errorf("the log was written for corpus %s, not %s", h.Answers, id)
+	}
+	if e.allowedID != h.Guesses {
+		return fmt.Errorf("the log was written for allowed guesses %q, not %q", h.Guesses, e.allowedID)
+	}
+	e.SetSeed(h.Seed)
+	e.SetHardMode(h.HardMode)
+	e.SetAdversarial(h.Adversarial)
+	e.SetLying(h.Lying)
+	e.SetTurnLimit(h.TurnLimit)
+	return nil
+}
+
+// NewLogEntry returns the log entry for a game played by the bot.
+func NewLogEntry(bot string, m *MultiGame) LogEntry {
+	entry := LogEntry{Bot: bot, Goals: m.Goals()}
+	for _, board := range m.boards {
+		if board.Turns() > len(entry.Guesses) {
+			entry.Guesses = board.Guesses()
+		}
+	}
+	for t := range entry.Guesses {
+		signatures := make([]string, len(m.boards))
+		for i, board := range m.boards {
+			if t < board.Turns() {
+				signatures[i] = board.signatures[t]
+			}
+		}
+		entry.Signatures = append(entry.Signatures, signatures)
+	}
+	return entry
+}
+
+// Replay plays the logged game again and checks that every signature is
+// the one in the log. It returns the game, played as far as it agrees
+// with the log, and an error describing the first difference.
+func (e *GtwEngine) Replay(entry LogEntry) (*MultiGame, error) {
+	m, err := e.StartMultiGame(entry.Goals)
+	if err != nil {
+		return nil, err
+	}
+	if len(entry.Signatures) != len(entry.Guesses) {
+		return m, fmt.Errorf("the log has %d guesses but %d signatures", len(entry.Guesses), len(entry.Signatures))
+	}
+	for t, guess := range entry.Guesses {
+		signatures, err := m.Score(guess)
+		if err != nil {
+			return m, fmt.Errorf("guess %d %s: %s", t+1, guess, err)
+		}
+		logged := entry.Signatures[t]
+		if len(logged) != len(signatures) {
+			return m, fmt.Errorf("guess %d %s: the log has %d boards, expected %d", t+1, guess, len(logged), len(signatures))
+		}
+		for i := range signatures {
+			if signatures[i] != logged[i] {
+				return m, fmt.Errorf("guess %d %s board %d: signature %s, the log has %s", t+1, guess, i+1, signatures[i], logged[i])
+			}
+		}
+	}
+	return m, nil
+}
+
+// LogWriter writes a game log. It is safe for concurrent use.
+type LogWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewLogWriter writes the header of a log and returns a LogWriter for
+// its entries.
+func NewLogWriter(w io.Writer, h LogHeader) (*LogWriter, error) {
+	l := &LogWriter{w: w}
+	if err := l.write(h); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write writes an entry to the log.
+func (l *LogWriter) Write(entry LogEntry) error {
+	return l.write(entry)
+}
+
+func (l *LogWriter) write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err = l.w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadLog reads a game log written by a LogWriter.
+func ReadLog(r io.Reader) (LogHeader, []LogEntry, error) {
+	var header LogHeader
+	var entries []LogEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line == 1 {
+			if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+				return header, nil, fmt.Errorf("line 1: bad log header: %s", err)
+			}
+			continue
+		}
+		var entry LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return header, nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, err
+	}
+	if line == 0 {
+		return header, nil, fmt.Errorf("empty log")
+	}
+	return header, entries, nil
+}
@@ -0,0 +1,97 @@
+package gtw
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGameLogReplay(t *testing.T) {
+	engine := New(treeAnswers)
+	engine.SetAllowedGuesses(treeGuesses)
+	engine.SetLying(true)
+	engine.SetSeed(7)
+	engine.SetTurnLimit(6)
+	m, _ := engine.StartMultiGame([]string{"cigar", "rebut"})
+	for _, guess := range []string{"sissy", "cigar", "humph", "rebut"} {
+		if _, err := m.Score(guess); err != nil {
+			t.Fatal("Score", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	l, err := NewLogWriter(&buf, engine.LogHeader())
+	if err != nil {
+		t.Fatal("NewLogWriter", err)
+	}
+	l.Write(NewLogEntry("test", m))
+	header, entries, err := ReadLog(&buf)
+	if err != nil || len(entries) != 1 {
+		t.Fatal("ReadLog", err, len(entries))
+	}
+	entry := entries[0]
+	if entry.Bot != "test" || len(entry.Guesses) != 4 || entry.Signatures[1][0] != "+++++" || entry.Signatures[3][0] != "" {
+		t.Error("wrong log entry", entry)
+	}
+
+	// A new engine with the same words plays the game the same way,
+	// including the lies, once it has the settings from the header.
+	replay := New(treeAnswers)
+	replay.SetAllowedGuesses(treeGuesses)
+	if err := replay.ApplyLogHeader(header); err != nil {
+		t.Fatal("ApplyLogHeader", err)
+	}
+	if !replay.Lying() || replay.TurnLimit() != 6 {
+		t.Error("settings not applied from the header", header)
+	}
+	game, err := replay.Replay(entry)
+	if err != nil || !game.Won() {
+		t.Error("Replay", err)
+	}
+
+	entry.Signatures[2][1] = "+++++"
+	if _, err := replay.Replay(entry); err == nil || !strings.Contains(err.Error(), "guess 3 humph board 2") {
+		t.Error("Replay did not find the changed signature", err)
+	}
+}
+
+func TestApplyLogHeaderWrongCorpus(t *testing.T) {
+	header := New(treeAnswers).LogHeader()
+	engine := New(loadedTestData)
+	if err := engine.ApplyLogHeader(header); err == nil {
+		t.Error("log header accepted for a different corpus")
+	}
+	engine = New(treeAnswers)
+	engine.SetAllowedGuesses(treeGuesses)
+	if err := engine.ApplyLogHeader(header); err == nil {
+		t.Error("log header accepted for different allowed guesses")
+	}
+}
+
+func TestReplayLyingWithUndoAndAdversary(t *testing.T) {
+	for _, adversarial := range []bool{false, true} {
+		engine := New(treeAnswers)
+		engine.SetLying(true)
+		engine.SetAdversarial(adversarial)
+		engine.SetSeed(11)
+		// The start goal isn't logged in adversarial mode, so it must
+		// not matter to the lies.
+		m, _ := engine.StartMultiGame([]string{"humph"})
+		// Taking back guesses must not change the lies told later
+		for _, guess := range []string{"sissy", "rebut", "cigar"} {
+			m.Score(guess)
+			if err := m.Undo(); err != nil && !adversarial {
+				t.Fatal("Undo", err)
+			}
+		}
+		for _, guess := range []string{"rebut", "cigar", "sissy", "humph"} {
+			if !m.Over() {
+				m.Score(guess)
+			}
+		}
+		entry := NewLogEntry("test", m)
+		if _, err := engine.Replay(entry); err != nil {
+			t.Error("Replay", adversarial, err)
+		}
+	}
+}
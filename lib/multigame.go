@@ -0,0 +1,134 @@
+package gtw
+
+import (
+	"fmt"
+)
+
+// MultiGame is a game played on several boards at once, each having its
+// own goal word (this is known elsewhere as Dordle, Quordle or Octordle
+// for 2, 4 or 8 boards). Each guess is scored against every board that
+// has not been solved yet. The game is won when every board is solved.
+// The boards are Games; a board's history holds the guesses made while
+// it was unsolved, including the one that solved it. The turn limit
+// applies to the whole game rather than to each board.
+type MultiGame struct {
+	boards    []*Game
+	turns     int
+	turnLimit int // 0 means no limit
+}
+
+// StartMultiGame creates a game having one board for each goal word.
+// Each goal word must have the engine's word length. The engine's turn
+// limit applies to the game as a whole. In adversarial mode the goal
+// words are ignored (see StartGame), so every board is the same.
+func (e *GtwEngine) StartMultiGame(goals []string) (*MultiGame, error) {
+	if len(goals) == 0 {
+		return nil, fmt.Errorf("a game needs at least one board")
+	}
+	m := &MultiGame{turnLimit: e.turnLimit}
+	for _, goal := range goals {
+		board, err := e.StartGame(goal)
+		if err != nil {
+			return nil, err
+		}
+		board.turnLimit = 0
+		m.boards = append(m.boards, board)
+	}
+	return m, nil
+}
+
+// Score scores the guess against every unsolved board and returns the
+// signature for each board, in the same order as the goal words. The
+// signature is "" for a board that was already solved. The guess is
+// rejected, and no board is changed, if any unsolved board would reject
+// it (see Game.Score) or the game is over.
+func (m *MultiGame) Score(guess string) ([]string, error) {
+	if m.Over() {
+		return nil, fmt.Errorf("the game is over")
+	}
+	for _, board := range m.boards {
+		if !board.Won() {
+			if err := board.check(guess); err != nil {
+				return nil, err
+			}
+		}
+	}
+	signatures := make([]string, len(m.boards))
+	for i, board := range m.boards {
+		if !board.Won() {
+			signatures[i], _ = board.score(guess)
+		}
+	}
+	m.turns++
+	return signatures, nil
+}
+
+// Undo takes back the last scored guess on every board it was scored
+// against (see Game.Undo).
+func (m *MultiGame) Undo() error {
+	if m.turns == 0 {
+		return fmt.Errorf("there is no guess to take back")
+	}
+	for _, board := range m.boards {
+		if board.Turns() == m.turns {
+			if err := board.Undo(); err != nil {
+				return err
+			}
+		}
+	}
+	m.turns--
+	return nil
+}
+
+// Boards returns the boards of the game in the order of the goal words.
+// The boards should not be scored directly.
+func (m *MultiGame) Boards() []*Game {
+	return m.boards
+}
+
+// Goals returns the goal word of each board (see Game.Cheat).
+func (m *MultiGame) Goals() []string {
+	goals := make([]string, len(m.boards))
+	for i, board := range m.boards {
+		goals[i] = board.Cheat()
+	}
+	return goals
+}
+
+// Turns returns the number of scored guesses.
+func (m *MultiGame) Turns() int {
+	return m.turns
+}
+
+// TurnLimit returns the number of scored guesses allowed in the game,
+// 0 meaning no limit.
+func (m *MultiGame) TurnLimit() int {
+	return m.turnLimit
+}
+
+// Solved returns the number of boards solved so far.
+func (m *MultiGame) Solved() int {
+	solved := 0
+	for _, board := range m.boards {
+		if board.Won() {
+			solved++
+		}
+	}
+	return solved
+}
+
+// Won returns true if every board has been solved.
+func (m *MultiGame) Won() bool {
+	return m.Solved() == len(m.boards)
+}
+
+// Lost returns true if the turn limit was reached before every board
+// was solved.
+func (m *MultiGame) Lost() bool {
+	return !m.Won() && m.turnLimit > 0 && m.turns >= m.turnLimit
+}
+
+// Over returns true if the game has been won or lost.
+func (m *MultiGame) Over() bool {
+	return m.Won() || m.Lost()
+}
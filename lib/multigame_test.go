@@ -0,0 +1,79 @@
+package gtw
+
+import (
+	"testing"
+)
+
+func TestMultiGame(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.SetTurnLimit(5)
+	m, err := engine.StartMultiGame([]string{"three", "blind"})
+	if err != nil {
+		t.Fatal("StartMultiGame", err)
+	}
+	signatures, err := m.Score("blind")
+	if err != nil || len(signatures) != 2 || signatures[0] != "#####" || signatures[1] != "+++++" {
+		t.Error("wrong signatures for blind", signatures, err)
+	}
+	if m.Solved() != 1 || m.Won() || m.Over() {
+		t.Error("one board of two should be solved", m.Solved())
+	}
+	signatures, err = m.Score("mices")
+	if err != nil || signatures[0] != "###+#" || signatures[1] != "" {
+		t.Error("wrong signatures for mices", signatures, err)
+	}
+	m.Score("three")
+	if !m.Won() || m.Turns() != 3 {
+		t.Error("game should be won in 3 turns", m.Turns())
+	}
+	boards := m.Boards()
+	if boards[0].Turns() != 3 || boards[1].Turns() != 1 {
+		t.Error("boards should only record guesses made while unsolved", boards[0].Turns(), boards[1].Turns())
+	}
+	if _, err := m.Score("three"); err == nil {
+		t.Error("guess scored after the game was over")
+	}
+}
+
+func TestMultiGameRejects(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	engine.SetHardMode(true)
+	engine.SetTurnLimit(2)
+	m, _ := engine.StartMultiGame([]string{"three", "mices"})
+	m.Score("there")
+	// "there" reveals +++ on the first board, so "blind" breaks hard mode
+	if _, err := m.Score("blind"); err == nil {
+		t.Error("hard mode violation on one board was accepted")
+	}
+	if m.Boards()[1].Turns() != 1 {
+		t.Error("a rejected guess changed a board")
+	}
+	m.Score("three")
+	if !m.Lost() || m.Won() {
+		t.Error("game should be lost at the turn limit", m.Solved())
+	}
+	if _, err := engine.StartMultiGame(nil); err == nil {
+		t.Error("game with no boards accepted")
+	}
+}
+
+func TestMultiGameUndo(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	m, _ := engine.StartMultiGame([]string{"three", "blind"})
+	if err := m.Undo(); err == nil {
+		t.Error("Undo before the first guess")
+	}
+	m.Score("blind")
+	m.Score("mices")
+	if err := m.Undo(); err != nil {
+		t.Fatal("Undo", err)
+	}
+	boards := m.Boards()
+	if m.Turns() != 1 || boards[0].Turns() != 1 || boards[1].Turns() != 1 || !boards[1].Won() {
+		t.Error("wrong boards after taking back mices", m.Turns(), boards[0].Turns(), boards[1].Turns())
+	}
+	m.Undo()
+	if m.Turns() != 0 || m.Solved() != 0 {
+		t.Error("taking back blind should unsolve its board", m.Turns(), m.Solved())
+	}
+}
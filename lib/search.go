@@ -0,0 +1,220 @@
+package gtw
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// TreeSearch searches for a decision tree that minimizes the expected
+// number of guesses needed to find a goal word chosen uniformly from
+// Answers, guessing from Guesses (which should include the answers).
+//
+// At each node of the tree, the candidate guesses are ranked by the
+// expected number of answers remaining after the guess, and only the
+// best Beam of them are searched. The search is exact, and the tree
+// optimal, when Beam is 0, but that is very expensive for large lists.
+// Subtrees are searched with branch and bound, and results are cached
+// by candidate set, so a TreeSearch should be reused across calls. A
+// TreeSearch is not safe for concurrent use; to search in parallel,
+// give each goroutine its own TreeSearch and divide the RootGuesses
+// between them.
+type TreeSearch struct {
+	Answers []string
+	Guesses []string
+	Beam    int
+
+	memo map[string]searchResult
+}
+
+type searchResult struct {
+	cost  int
+	guess string
+}
+
+// RootGuesses returns the first guesses the search will consider, best
+// first according to the ranking heuristic.
+func (s *TreeSearch) RootGuesses() []string {
+	return s.rank(s.Answers)
+}
+
+// Cost returns the smallest total number of guesses, over all the
+// answers, of a tree whose first guess is the argument.
+func (s *TreeSearch) Cost(first string) int {
+	return s.costWithGuess(first, s.Answers, math.MaxInt32)
+}
+
+// Tree returns the best tree having the argument as its first guess.
+func (s *TreeSearch) Tree(first string) *DecisionTree {
+	paths := make(map[string][]string)
+	s.buildPaths(first, s.Answers, nil, paths)
+	t, _ := NewDecisionTree(paths)
+	return t
+}
+
+// Best searches all the root guesses and returns the best tree.
+func (s *TreeSearch) Best() *DecisionTree {
+	best := ""
+	bestCost := math.MaxInt32
+	for _, g := range s.RootGuesses() {
+		if c := s.Cost(g); c < bestCost {
+			best = g
+			bestCost = c
+		}
+	}
+	return s.Tree(best)
+}
+
+// solve returns the best cost and guess for a set of candidates. The
+// cost is the total number of guesses, including the one chosen here,
+// to find every candidate.
+func (s *TreeSearch) solve(candidates []string) searchResult {
+	switch len(candidates) {
+	case 1:
+		return searchResult{1, candidates[0]}
+	case 2:
+		return searchResult{3, candidates[0]}
+	}
+	key := strings.Join(candidates, ",")
+	if r, ok := s.memo[key]; ok {
+		return r
+	}
+
+	best := searchResult{math.MaxInt32, ""}
+	for _, g := range s.rank(candidates) {
+		if c := s.costWithGuess(g, candidates, best.cost); c < best.cost {
+			best = searchResult{c, g}
+		}
+	}
+	if s.memo == nil {
+		s.memo = make(map[string]searchResult)
+	}
+	s.memo[key] = best
+	return best
+}
+
+// costWithGuess returns the total cost of the candidates if the guess
+// is made next. It gives up, returning bound or more, as soon as it
+// is clear the cost cannot be less than bound.
+func (s *TreeSearch) costWithGuess(guess string, candidates []string, bound int) int {
+	buckets, signatures := partitionWords(guess, candidates)
+	if len(buckets) == 1 && buckets[signatures[0]][0] != guess {
+		return math.MaxInt32 // the guess tells us nothing
+	}
+
+	// Every candidate needs this guess, and each bucket of b > 1 words
+	// needs at least 2b-1 more guesses (1 for a single word).
+	cost := len(candidates)
+	lowerBound := cost
+	for _, sig := range signatures {
+		lowerBound += minimumCost(len(buckets[sig]), sig)
+	}
+	if lowerBound >= bound {
+		return lowerBound
+	}
+	for _, sig := range signatures {
+		if isWin(sig) {
+			continue
+		}
+		lowerBound -= minimumCost(len(buckets[sig]), sig)
+		cost += s.solve(buckets[sig]).cost
+		if cost+lowerBound-len(candidates) >= bound {
+			return bound
+		}
+	}
+	return cost
+}
+
+func (s *TreeSearch) buildPaths(guess string, candidates []string, path []string, paths map[string][]string) {
+	path = append(path[:len(path):len(path)], guess)
+	buckets, signatures := partitionWords(guess, candidates)
+	for _, sig := range signatures {
+		bucket := buckets[sig]
+		if isWin(sig) {
+			paths[guess] = path
+			continue
+		}
+		s.buildPaths(s.solve(bucket).guess, bucket, path, paths)
+	}
+}
+
+// rank returns the guesses worth considering for the candidates, best
+// first, limited to Beam guesses if Beam is not 0. Guesses are ranked
+// by the sum of the squares of their bucket sizes, which is proportional
+// to the expected number of candidates left after the guess. Ties go to
+// guesses that are candidates themselves.
+func (s *TreeSearch) rank(candidates []string) []string {
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		isCandidate[c] = true
+	}
+	type ranked struct {
+		guess     string
+		score     int
+		candidate bool
+	}
+	var pool []ranked
+	for _, g := range s.Guesses {
+		counts := make(map[string]int)
+		for _, c := range candidates {
+			signature, _ := Signature(g, c)
+			counts[signature]++
+		}
+		if len(counts) == 1 && !isCandidate[g] {
+			continue
+		}
+		score := 0
+		for _, n := range counts {
+			score += n * n
+		}
+		pool = append(pool, ranked{g, score, isCandidate[g]})
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		if pool[i].score != pool[j].score {
+			return pool[i].score < pool[j].score
+		}
+		return pool[i].candidate && !pool[j].candidate
+	})
+	if s.Beam > 0 && len(pool) > s.Beam {
+		pool = pool[:s.Beam]
+	}
+	result := make([]string, len(pool))
+	for i, r := range pool {
+		result[i] = r.guess
+	}
+	return result
+}
+
+// partitionWords groups the candidates by the signature the guess would
+// produce for them. The signatures are returned in sorted order so the
+// search is deterministic.
+func partitionWords(guess string, candidates []string) (map[string][]string, []string) {
+	buckets := make(map[string][]string)
+	for _, c := range candidates {
+		signature, _ := Signature(guess, c)
+		buckets[signature] = append(buckets[signature], c)
+	}
+	signatures := make([]string, 0, len(buckets))
+	for sig := range buckets {
+		signatures = append(signatures, sig)
+	}
+	sort.Strings(signatures)
+	return buckets, signatures
+}
+
+// minimumCost is a lower bound on the number of guesses needed after
+// a guess to find all the words in a bucket of the given size.
+func minimumCost(size int, signature string) int {
+	switch {
+	case isWin(signature):
+		return 0
+	case size == 1:
+		return 1
+	default:
+		return 2*size - 1
+	}
+}
+
+func isWin(signature string) bool {
+	return strings.Trim(signature, string(LETTER_CORRECT)) == ""
+}
@@ -0,0 +1,54 @@
+package gtw
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The squares used by ShareGrid for each kind of letter, as emoji and as
+// plain ASCII for terminals and chat systems that can't show emoji.
+var shareEmoji = map[rune]string{LETTER_CORRECT: "🟩", LETTER_IN_WORD: "🟨", LETTER_WRONG: "⬛"}
+var shareASCII = map[rune]string{LETTER_CORRECT: "G", LETTER_IN_WORD: "Y", LETTER_WRONG: "-"}
+
+// ShareGrid returns the familiar block of colored squares used to share
+// the result of a finished game without giving away the goal word. The
+// first line is a header holding the puzzle number and the number of
+// guesses out of the turn limit, e.g. "GTW 245 4/6", with X for the
+// number of guesses if the game was lost. It is followed by one row of
+// squares for each signature: green for a correct letter, yellow for a
+// letter in the word and black for a letter not in the word. If ascii is
+// set the squares are the letters G and Y and the character '-'. A turn
+// limit of 0 means no limit and is left out of the header.
+func ShareGrid(puzzle int, signatures []string, won bool, turnLimit int, ascii bool) string {
+	squares := shareEmoji
+	if ascii {
+		squares = shareASCII
+	}
+
+	var result strings.Builder
+	guesses := "X"
+	if won {
+		guesses = fmt.Sprintf("%d", len(signatures))
+	}
+	fmt.Fprintf(&result, "GTW %d %s", puzzle, guesses)
+	if turnLimit > 0 {
+		fmt.Fprintf(&result, "/%d", turnLimit)
+	}
+	result.WriteString("\n")
+	for _, signature := range signatures {
+		result.WriteString("\n")
+		for _, r := range signature {
+			s, ok := squares[r]
+			if !ok {
+				s = "?"
+			}
+			result.WriteString(s)
+		}
+	}
+	return result.String()
+}
+
+// Share returns ShareGrid for the game's signatures.
+func (g *Game) Share(puzzle int, ascii bool) string {
+	return ShareGrid(puzzle, g.signatures, g.won, g.turnLimit, ascii)
+}
@@ -0,0 +1,32 @@
+package gtw
+
+import (
+	"testing"
+)
+
+func TestShareGrid(t *testing.T) {
+	signatures := []string{"#*###", "++#*#", "+++++"}
+	expected := "GTW 245 3/6\n\n⬛🟨⬛⬛⬛\n🟩🟩⬛🟨⬛\n🟩🟩🟩🟩🟩"
+	if grid := ShareGrid(245, signatures, true, 6, false); grid != expected {
+		t.Errorf("ShareGrid: got\n%s\nexpected\n%s", grid, expected)
+	}
+	expected = "GTW 7 X/2\n\n-Y---\nGG-Y-"
+	if grid := ShareGrid(7, signatures[:2], false, 2, true); grid != expected {
+		t.Errorf("ShareGrid ascii: got\n%s\nexpected\n%s", grid, expected)
+	}
+	expected = "GTW 7 1\n\nGGGGG"
+	if grid := ShareGrid(7, signatures[2:], true, 0, true); grid != expected {
+		t.Errorf("ShareGrid without limit: got\n%s\nexpected\n%s", grid, expected)
+	}
+}
+
+func TestGameShare(t *testing.T) {
+	engine := New(loadTestCorpus(t))
+	game, _ := engine.StartGame("blind")
+	game.Score("mices")
+	game.Score("blind")
+	expected := "GTW 1 2\n\n-Y---\nGGGGG"
+	if grid := game.Share(1, true); grid != expected {
+		t.Errorf("Game.Share: got\n%s\nexpected\n%s", grid, expected)
+	}
+}
@@ -0,0 +1,119 @@
+package gtw
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// PlayerStats is a player's record for one corpus and one mode.
+type PlayerStats struct {
+	Played        int   `json:"played"`
+	Won           int   `json:"won"`
+	CurrentStreak int   `json:"currentStreak"`
+	LongestStreak int   `json:"longestStreak"`
+	Distribution  []int `json:"distribution"` // Distribution[n-1] is the number of games won in n guesses
+}
+
+// Record adds the result of a game to the statistics. A win extends
+// the current streak and a loss ends it.
+func (s *PlayerStats) Record(won bool, guesses int) {
+	s.Played++
+	if !won {
+		s.CurrentStreak = 0
+		return
+	}
+	s.Won++
+	s.CurrentStreak++
+	if s.CurrentStreak > s.LongestStreak {
+		s.LongestStreak = s.CurrentStreak
+	}
+	if guesses < 1 {
+		guesses = 1
+	}
+	for len(s.Distribution) < guesses {
+		s.Distribution = append(s.Distribution, 0)
+	}
+	s.Distribution[guesses-1]++
+}
+
+// WinPercent returns the percentage of games played that were won.
+func (s *PlayerStats) WinPercent() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+	return 100 * float64(s.Won) / float64(s.Played)
+}
+
+// StatsStore holds a player's statistics, kept in a JSON file between
+// runs. The statistics for each corpus and mode are kept apart; see
+// StatsKey.
+type StatsStore struct {
+	path  string
+	Stats map[string]*PlayerStats `json:"stats"`
+}
+
+// DefaultStatsPath returns the path of the statistics file in the
+// user's configuration directory, e.g. ~/.config/gtw/stats.json.
+func DefaultStatsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gtw", "stats.json"), nil
+}
+
+// StatsKey returns the key of the statistics for a corpus, given by its
+// CorpusID, and a mode such as "normal", "hard" or "daily".
+func StatsKey(corpusID string, mode string) string {
+	return corpusID + "/" + mode
+}
+
+// LoadStats reads the statistics file. A missing file is not an error;
+// it holds no statistics yet.
+func LoadStats(path string) (*StatsStore, error) {
+	store := &StatsStore{path: path, Stats: make(map[string]*PlayerStats)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, err
+	}
+	if store.Stats == nil {
+		store.Stats = make(map[string]*PlayerStats)
+	}
+	return store, nil
+}
+
+// Get returns the statistics for the key, creating them if needed.
+func (s *StatsStore) Get(key string) *PlayerStats {
+	stats, ok := s.Stats[key]
+	if !ok {
+		stats = &PlayerStats{}
+		s.Stats[key] = stats
+	}
+	return stats
+}
+
+// Save writes the statistics back to the file they were loaded from,
+// creating its directory if needed. The file is replaced rather than
+// rewritten, so it isn't left half written if the program is stopped.
+func (s *StatsStore) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
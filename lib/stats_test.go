@@ -0,0 +1,57 @@
+package gtw
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlayerStatsRecord(t *testing.T) {
+	var s PlayerStats
+	s.Record(true, 3)
+	s.Record(true, 5)
+	s.Record(false, 6)
+	s.Record(true, 3)
+	if s.Played != 4 || s.Won != 3 || s.CurrentStreak != 1 || s.LongestStreak != 2 {
+		t.Error("wrong counts", s)
+	}
+	if len(s.Distribution) != 5 || s.Distribution[2] != 2 || s.Distribution[4] != 1 {
+		t.Error("wrong distribution", s.Distribution)
+	}
+	if p := s.WinPercent(); p != 75 {
+		t.Error("wrong win percentage", p)
+	}
+}
+
+func TestStatsStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gtw-stats-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gtw", "stats.json")
+
+	store, err := LoadStats(path)
+	if err != nil {
+		t.Fatal("LoadStats of a missing file", err)
+	}
+	normal := StatsKey(CorpusID(treeAnswers), "normal")
+	hard := StatsKey(CorpusID(treeAnswers), "hard")
+	store.Get(normal).Record(true, 4)
+	store.Get(hard).Record(false, 6)
+	if err := store.Save(); err != nil {
+		t.Fatal("Save", err)
+	}
+
+	store, err = LoadStats(path)
+	if err != nil {
+		t.Fatal("LoadStats", err)
+	}
+	if s := store.Get(normal); s.Played != 1 || s.Won != 1 || s.Distribution[3] != 1 {
+		t.Error("normal statistics not kept", s)
+	}
+	if s := store.Get(hard); s.Played != 1 || s.Won != 0 {
+		t.Error("hard statistics not kept apart", s)
+	}
+}
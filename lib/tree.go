@@ -0,0 +1,118 @@
+package gtw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DecisionTree is a complete strategy for playing a goal list: for each
+// goal word it holds the sequence of guesses that finds it, ending with
+// the goal word itself. Because the guesses depend only on the signatures
+// of earlier guesses, the paths form a tree. A DecisionTree is created
+// by a TreeSearch or read from a file written by WriteTree.
+type DecisionTree struct {
+	paths map[string][]string // goal word -> guesses
+	next  map[string]string   // history key -> next guess
+}
+
+// NewDecisionTree builds a tree from the guess path for each goal word.
+// An error is returned if the paths are not consistent with each other,
+// i.e. if the same history would lead to different guesses.
+func NewDecisionTree(paths map[string][]string) (*DecisionTree, error) {
+	t := &DecisionTree{paths: paths, next: make(map[string]string)}
+	for goal, path := range paths {
+		if len(path) == 0 || path[len(path)-1] != goal {
+			return nil, fmt.Errorf("path for %s does not end with the goal word", goal)
+		}
+		var signatures []string
+		for i, guess := range path {
+			key := treeKey(path[:i], signatures)
+			if previous, ok := t.next[key]; ok && previous != guess {
+				return nil, fmt.Errorf("paths disagree after %v: %s and %s", path[:i], previous, guess)
+			}
+			t.next[key] = guess
+			signature, _ := Signature(guess, goal)
+			signatures = append(signatures, signature)
+		}
+	}
+	return t, nil
+}
+
+// Next returns the guess the tree makes after the given guesses were
+// scored with the given signatures. The result is false if the history
+// is not in the tree.
+func (t *DecisionTree) Next(guesses []string, signatures []string) (string, bool) {
+	guess, ok := t.next[treeKey(guesses, signatures)]
+	return guess, ok
+}
+
+// Path returns the guesses the tree makes when the argument is the goal.
+func (t *DecisionTree) Path(goal string) []string {
+	return t.paths[goal]
+}
+
+// Cost returns the total number of guesses needed to find every goal
+// word in the tree. Divide by Size for the expected number of guesses.
+func (t *DecisionTree) Cost() int {
+	cost := 0
+	for _, path := range t.paths {
+		cost += len(path)
+	}
+	return cost
+}
+
+// Size returns the number of goal words in the tree.
+func (t *DecisionTree) Size() int {
+	return len(t.paths)
+}
+
+func treeKey(guesses []string, signatures []string) string {
+	var key strings.Builder
+	for i := range guesses {
+		key.WriteString(guesses[i])
+		key.WriteByte(' ')
+		key.WriteString(signatures[i])
+		key.WriteByte(' ')
+	}
+	return key.String()
+}
+
+// WriteTree writes the tree in a text format with one line per goal
+// word. Each line holds the guesses for that goal separated by spaces,
+// so the last word on each line is the goal. The lines are sorted.
+// Lines beginning with '#' are comments.
+func WriteTree(w io.Writer, t *DecisionTree) error {
+	lines := make([]string, 0, len(t.paths))
+	for _, path := range t.paths {
+		lines = append(lines, strings.Join(path, " "))
+	}
+	sort.Strings(lines)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %d goal words, %d guesses, %.4f guesses per goal\n",
+		t.Size(), t.Cost(), float64(t.Cost())/float64(t.Size()))
+	for _, line := range lines {
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// ReadTree reads a tree written by WriteTree.
+func ReadTree(r io.Reader) (*DecisionTree, error) {
+	paths := make(map[string][]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		path := strings.Fields(line)
+		paths[path[len(path)-1]] = path
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewDecisionTree(paths)
+}
@@ -0,0 +1,121 @@
+package gtw
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+var treeAnswers = []string{
+	"cigar", "rebut", "sissy", "humph", "awake", "blush", "focal", "evade",
+	"naval", "serve", "heath", "dwarf", "model", "karma", "stink", "grade",
+	"quiet", "bench", "abate", "feign", "major", "death", "fresh", "crust",
+}
+
+var treeGuesses = append([]string{"salet", "crane", "pound", "lymph"}, treeAnswers...)
+
+// bruteForceCost finds the optimal total cost with no beam and no pruning.
+func bruteForceCost(candidates []string, guesses []string) int {
+	if len(candidates) == 1 {
+		return 1
+	}
+	best := math.MaxInt32
+	for _, g := range guesses {
+		buckets, signatures := partitionWords(g, candidates)
+		if len(buckets) == 1 && buckets[signatures[0]][0] != g {
+			continue
+		}
+		cost := len(candidates)
+		for _, sig := range signatures {
+			if !isWin(sig) {
+				cost += bruteForceCost(buckets[sig], guesses)
+			}
+		}
+		if cost < best {
+			best = cost
+		}
+	}
+	return best
+}
+
+func TestTreeSearchOptimal(t *testing.T) {
+	search := &TreeSearch{Answers: treeAnswers, Guesses: treeGuesses}
+	tree := search.Best()
+	if tree.Size() != len(treeAnswers) {
+		t.Fatal("tree does not cover all the answers", tree.Size())
+	}
+	if expected := bruteForceCost(treeAnswers, treeGuesses); tree.Cost() != expected {
+		t.Error("tree is not optimal: cost", tree.Cost(), "expected", expected)
+	}
+
+	beamed := &TreeSearch{Answers: treeAnswers, Guesses: treeGuesses, Beam: 2}
+	if c := beamed.Best().Cost(); c < tree.Cost() {
+		t.Error("beam search beat the exact search", c, tree.Cost())
+	}
+}
+
+func TestTreeSearchRootGuess(t *testing.T) {
+	search := &TreeSearch{Answers: treeAnswers, Guesses: treeGuesses}
+	for _, first := range search.RootGuesses()[:3] {
+		tree := search.Tree(first)
+		if tree.Cost() != search.Cost(first) {
+			t.Error("Tree and Cost disagree for", first, tree.Cost(), search.Cost(first))
+		}
+		if guess, ok := tree.Next(nil, nil); !ok || guess != first {
+			t.Error("wrong first guess", guess, first)
+		}
+	}
+}
+
+func TestDecisionTreePlay(t *testing.T) {
+	search := &TreeSearch{Answers: treeAnswers, Guesses: treeGuesses}
+	tree := search.Best()
+	engine := New(treeAnswers)
+	for _, goal := range treeAnswers {
+		game, _ := engine.StartGame(goal)
+		for !game.Won() && game.Turns() < 10 {
+			guess, ok := tree.Next(game.Guesses(), game.Signatures())
+			if !ok {
+				t.Fatal("history not in tree", game.Guesses())
+			}
+			game.Score(guess)
+		}
+		if !game.Won() || game.Turns() != len(tree.Path(goal)) {
+			t.Error("tree did not find", goal, game.Guesses(), tree.Path(goal))
+		}
+	}
+}
+
+func TestReadWriteTree(t *testing.T) {
+	tree := (&TreeSearch{Answers: treeAnswers, Guesses: treeGuesses, Beam: 3}).Best()
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, tree); err != nil {
+		t.Fatal("WriteTree", err)
+	}
+	read, err := ReadTree(&buf)
+	if err != nil {
+		t.Fatal("ReadTree", err)
+	}
+	if read.Size() != tree.Size() || read.Cost() != tree.Cost() {
+		t.Error("tree changed by writing and reading", read.Size(), read.Cost())
+	}
+	for _, goal := range treeAnswers {
+		if len(read.Path(goal)) != len(tree.Path(goal)) {
+			t.Error("wrong path for", goal, read.Path(goal))
+		}
+	}
+}
+
+func TestNewDecisionTreeInconsistent(t *testing.T) {
+	paths := map[string][]string{
+		"cigar": {"salet", "cigar"},
+		"rebut": {"crane", "rebut"},
+	}
+	if _, err := NewDecisionTree(paths); err == nil {
+		t.Error("inconsistent paths accepted")
+	}
+	paths = map[string][]string{"cigar": {"salet"}}
+	if _, err := NewDecisionTree(paths); err == nil {
+		t.Error("path not ending with the goal accepted")
+	}
+}